@@ -31,6 +31,10 @@ type Common interface {
 	AuthVerify(ctx context.Context, token string) ([]auth.Permission, error) //perm:read
 	// AuthNew creates a new token with the specified list of permissions.
 	AuthNew(ctx context.Context, perms []auth.Permission) ([]byte, error) //perm:admin
+	// AuthVerifyPayload checks whether the specified token is valid and returns its permissions and owner.
+	AuthVerifyPayload(ctx context.Context, token string) (*types.JWTPayload, error) //perm:read
+	// AuthNewOwner creates a new token with the specified list of permissions, restricted to the resources of the owner.
+	AuthNewOwner(ctx context.Context, perms []auth.Permission, owner string) ([]byte, error) //perm:admin
 
 	// MethodGroup: Log
 
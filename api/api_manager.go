@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 )
@@ -10,14 +12,28 @@ import (
 type Manager interface {
 	Common
 
-	GetStatistics(ctx context.Context, id types.ProviderID) (*types.ResourcesStatistics, error)         //perm:read
-	ProviderConnect(ctx context.Context, url string, provider *types.Provider) error                    //perm:admin
-	GetProviderList(ctx context.Context, option *types.GetProviderOption) ([]*types.Provider, error)    //perm:read
-	GetDeploymentList(ctx context.Context, opt *types.GetDeploymentOption) ([]*types.Deployment, error) //perm:read
-	CreateDeployment(ctx context.Context, deployment *types.Deployment) error                           //perm:admin
-	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error                           //perm:admin
-	CloseDeployment(ctx context.Context, deployment *types.Deployment) error                            //perm:admin
-	GetLogs(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceLog, error)             //perm:read
-	GetEvents(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceEvent, error)         //perm:read
-	SetProperties(ctx context.Context, properties *types.Properties) error                              //perm:admin
+	GetStatistics(ctx context.Context, id types.ProviderID) (*types.ResourcesStatistics, error)                                          //perm:read
+	ProviderConnect(ctx context.Context, url string, provider *types.Provider) error                                                     //perm:admin
+	GetProviderList(ctx context.Context, option *types.GetProviderOption) ([]*types.Provider, error)                                     //perm:read
+	GetDeploymentList(ctx context.Context, opt *types.GetDeploymentOption) ([]*types.Deployment, error)                                  //perm:read
+	CreateDeployment(ctx context.Context, deployment *types.Deployment) error                                                            //perm:sign
+	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error                                                            //perm:sign
+	CloseDeployment(ctx context.Context, deployment *types.Deployment) error                                                             //perm:sign
+	ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error                                      //perm:write
+	RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error                                                  //perm:write
+	RenewDeployment(ctx context.Context, id types.DeploymentID, duration time.Duration) error                                            //perm:write
+	GetLogs(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceLog, error)                                              //perm:read
+	GetEvents(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceEvent, error)                                          //perm:read
+	GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error)                                    //perm:read
+	WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error)                                           //perm:read
+	StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error)                          //perm:read
+	ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error) //perm:write
+	CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error                        //perm:write
+	CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error)              //perm:write
+	SetProperties(ctx context.Context, properties *types.Properties) error                                                               //perm:admin
+	GetLedger(ctx context.Context, opt *types.GetLedgerOption) ([]*types.LedgerEntry, error)                                             //perm:read
+	AddCredit(ctx context.Context, owner string, amount float64) error                                                                   //perm:admin
+	GetAccount(ctx context.Context, owner string) (*types.Account, error)                                                                //perm:read
+	GetDeploymentRevisions(ctx context.Context, id types.DeploymentID) ([]*types.DeploymentRevision, error)                              //perm:read
+	RollbackDeployment(ctx context.Context, id types.DeploymentID, revision int64, sig *types.Signature) error                           //perm:sign
 }
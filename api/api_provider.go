@@ -2,19 +2,30 @@ package api
 
 import (
 	"context"
+	"io"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/google/uuid"
 )
 
 type Provider interface {
-	GetStatistics(ctx context.Context) (*types.ResourcesStatistics, error)               //perm:read
-	GetDeployment(ctx context.Context, id types.DeploymentID) (*types.Deployment, error) //perm:read
-	CreateDeployment(ctx context.Context, deployment *types.Deployment) error            //perm:admin
-	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error            //perm:admin
-	CloseDeployment(ctx context.Context, deployment *types.Deployment) error             //perm:admin
-	GetLogs(ctx context.Context, id types.DeploymentID) ([]*types.ServiceLog, error)     //perm:read
-	GetEvents(ctx context.Context, id types.DeploymentID) ([]*types.ServiceEvent, error) //perm:read
+	GetStatistics(ctx context.Context) (*types.ResourcesStatistics, error)                                                               //perm:read
+	GetDeployment(ctx context.Context, id types.DeploymentID) (*types.Deployment, error)                                                 //perm:read
+	ListDeploymentIDs(ctx context.Context) ([]types.DeploymentID, error)                                                                 //perm:read
+	CreateDeployment(ctx context.Context, deployment *types.Deployment) error                                                            //perm:admin
+	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error                                                            //perm:admin
+	ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error                                      //perm:admin
+	RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error                                                  //perm:admin
+	CloseDeployment(ctx context.Context, deployment *types.Deployment) error                                                             //perm:admin
+	GetLogs(ctx context.Context, id types.DeploymentID) ([]*types.ServiceLog, error)                                                     //perm:read
+	GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error)                                    //perm:read
+	GetEvents(ctx context.Context, id types.DeploymentID) ([]*types.ServiceEvent, error)                                                 //perm:read
+	WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error)                                           //perm:read
+	StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error)                          //perm:read
+	ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error) //perm:admin
+	CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error                        //perm:admin
+	CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error)              //perm:admin
+	GetKubeSettings(ctx context.Context) (*types.KubeSettings, error)                                                                    //perm:read
 
 	Version(context.Context) (Version, error)   //perm:admin
 	Session(context.Context) (uuid.UUID, error) //perm:admin
@@ -4,10 +4,12 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 
-	"github.com/filecoin-project/go-jsonrpc/auth"
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/Filecoin-Titan/titan-container/journal/alerting"
+	"github.com/filecoin-project/go-jsonrpc/auth"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 )
@@ -18,8 +20,12 @@ type CommonStruct struct {
 	Internal struct {
 		AuthNew func(p0 context.Context, p1 []auth.Permission) ([]byte, error) `perm:"admin"`
 
+		AuthNewOwner func(p0 context.Context, p1 []auth.Permission, p2 string) ([]byte, error) `perm:"admin"`
+
 		AuthVerify func(p0 context.Context, p1 string) ([]auth.Permission, error) `perm:"read"`
 
+		AuthVerifyPayload func(p0 context.Context, p1 string) (*types.JWTPayload, error) `perm:"read"`
+
 		Closing func(p0 context.Context) (<-chan struct{}, error) `perm:"admin"`
 
 		Discover func(p0 context.Context) (types.OpenRPCDocument, error) `perm:"admin"`
@@ -45,14 +51,30 @@ type ManagerStruct struct {
 	CommonStruct
 
 	Internal struct {
-		CloseDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
+		AddCredit func(p0 context.Context, p1 string, p2 float64) error `perm:"admin"`
 
-		CreateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
+		CloseDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"sign"`
+
+		CopyFromDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) `perm:"write"`
+
+		CopyToDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error `perm:"write"`
+
+		CreateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"sign"`
+
+		ExecDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) `perm:"write"`
+
+		GetAccount func(p0 context.Context, p1 string) (*types.Account, error) `perm:"read"`
 
 		GetDeploymentList func(p0 context.Context, p1 *types.GetDeploymentOption) ([]*types.Deployment, error) `perm:"read"`
 
+		GetDeploymentMetrics func(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) `perm:"read"`
+
+		GetDeploymentRevisions func(p0 context.Context, p1 types.DeploymentID) ([]*types.DeploymentRevision, error) `perm:"read"`
+
 		GetEvents func(p0 context.Context, p1 *types.Deployment) ([]*types.ServiceEvent, error) `perm:"read"`
 
+		GetLedger func(p0 context.Context, p1 *types.GetLedgerOption) ([]*types.LedgerEntry, error) `perm:"read"`
+
 		GetLogs func(p0 context.Context, p1 *types.Deployment) ([]*types.ServiceLog, error) `perm:"read"`
 
 		GetProviderList func(p0 context.Context, p1 *types.GetProviderOption) ([]*types.Provider, error) `perm:"read"`
@@ -61,9 +83,21 @@ type ManagerStruct struct {
 
 		ProviderConnect func(p0 context.Context, p1 string, p2 *types.Provider) error `perm:"admin"`
 
+		RenewDeployment func(p0 context.Context, p1 types.DeploymentID, p2 time.Duration) error `perm:"write"`
+
+		RestartDeployment func(p0 context.Context, p1 types.DeploymentID, p2 string) error `perm:"write"`
+
+		RollbackDeployment func(p0 context.Context, p1 types.DeploymentID, p2 int64, p3 *types.Signature) error `perm:"sign"`
+
+		ScaleDeployment func(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error `perm:"write"`
+
 		SetProperties func(p0 context.Context, p1 *types.Properties) error `perm:"admin"`
 
-		UpdateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
+		StreamLogs func(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) `perm:"read"`
+
+		UpdateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"sign"`
+
+		WatchEvents func(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) `perm:"read"`
 	}
 }
 
@@ -75,21 +109,41 @@ type ProviderStruct struct {
 	Internal struct {
 		CloseDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
 
+		CopyFromDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) `perm:"admin"`
+
+		CopyToDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error `perm:"admin"`
+
 		CreateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
 
+		ExecDeployment func(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) `perm:"admin"`
+
 		GetDeployment func(p0 context.Context, p1 types.DeploymentID) (*types.Deployment, error) `perm:"read"`
 
+		GetDeploymentMetrics func(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) `perm:"read"`
+
 		GetEvents func(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceEvent, error) `perm:"read"`
 
+		GetKubeSettings func(p0 context.Context) (*types.KubeSettings, error) `perm:"read"`
+
 		GetLogs func(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceLog, error) `perm:"read"`
 
 		GetStatistics func(p0 context.Context) (*types.ResourcesStatistics, error) `perm:"read"`
 
+		ListDeploymentIDs func(p0 context.Context) ([]types.DeploymentID, error) `perm:"read"`
+
+		RestartDeployment func(p0 context.Context, p1 types.DeploymentID, p2 string) error `perm:"admin"`
+
+		ScaleDeployment func(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error `perm:"admin"`
+
 		Session func(p0 context.Context) (uuid.UUID, error) `perm:"admin"`
 
+		StreamLogs func(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) `perm:"read"`
+
 		UpdateDeployment func(p0 context.Context, p1 *types.Deployment) error `perm:"admin"`
 
 		Version func(p0 context.Context) (Version, error) `perm:"admin"`
+
+		WatchEvents func(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) `perm:"read"`
 	}
 }
 
@@ -107,6 +161,17 @@ func (s *CommonStub) AuthNew(p0 context.Context, p1 []auth.Permission) ([]byte,
 	return *new([]byte), ErrNotSupported
 }
 
+func (s *CommonStruct) AuthNewOwner(p0 context.Context, p1 []auth.Permission, p2 string) ([]byte, error) {
+	if s.Internal.AuthNewOwner == nil {
+		return *new([]byte), ErrNotSupported
+	}
+	return s.Internal.AuthNewOwner(p0, p1, p2)
+}
+
+func (s *CommonStub) AuthNewOwner(p0 context.Context, p1 []auth.Permission, p2 string) ([]byte, error) {
+	return *new([]byte), ErrNotSupported
+}
+
 func (s *CommonStruct) AuthVerify(p0 context.Context, p1 string) ([]auth.Permission, error) {
 	if s.Internal.AuthVerify == nil {
 		return *new([]auth.Permission), ErrNotSupported
@@ -118,6 +183,17 @@ func (s *CommonStub) AuthVerify(p0 context.Context, p1 string) ([]auth.Permissio
 	return *new([]auth.Permission), ErrNotSupported
 }
 
+func (s *CommonStruct) AuthVerifyPayload(p0 context.Context, p1 string) (*types.JWTPayload, error) {
+	if s.Internal.AuthVerifyPayload == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.AuthVerifyPayload(p0, p1)
+}
+
+func (s *CommonStub) AuthVerifyPayload(p0 context.Context, p1 string) (*types.JWTPayload, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *CommonStruct) Closing(p0 context.Context) (<-chan struct{}, error) {
 	if s.Internal.Closing == nil {
 		return nil, ErrNotSupported
@@ -206,6 +282,17 @@ func (s *CommonStub) Version(p0 context.Context) (APIVersion, error) {
 	return *new(APIVersion), ErrNotSupported
 }
 
+func (s *ManagerStruct) AddCredit(p0 context.Context, p1 string, p2 float64) error {
+	if s.Internal.AddCredit == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.AddCredit(p0, p1, p2)
+}
+
+func (s *ManagerStub) AddCredit(p0 context.Context, p1 string, p2 float64) error {
+	return ErrNotSupported
+}
+
 func (s *ManagerStruct) CloseDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.CloseDeployment == nil {
 		return ErrNotSupported
@@ -217,6 +304,28 @@ func (s *ManagerStub) CloseDeployment(p0 context.Context, p1 *types.Deployment)
 	return ErrNotSupported
 }
 
+func (s *ManagerStruct) CopyFromDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) {
+	if s.Internal.CopyFromDeployment == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.CopyFromDeployment(p0, p1, p2)
+}
+
+func (s *ManagerStub) CopyFromDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *ManagerStruct) CopyToDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error {
+	if s.Internal.CopyToDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.CopyToDeployment(p0, p1, p2, p3)
+}
+
+func (s *ManagerStub) CopyToDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error {
+	return ErrNotSupported
+}
+
 func (s *ManagerStruct) CreateDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.CreateDeployment == nil {
 		return ErrNotSupported
@@ -228,6 +337,28 @@ func (s *ManagerStub) CreateDeployment(p0 context.Context, p1 *types.Deployment)
 	return ErrNotSupported
 }
 
+func (s *ManagerStruct) ExecDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) {
+	if s.Internal.ExecDeployment == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.ExecDeployment(p0, p1, p2, p3)
+}
+
+func (s *ManagerStub) ExecDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *ManagerStruct) GetAccount(p0 context.Context, p1 string) (*types.Account, error) {
+	if s.Internal.GetAccount == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.GetAccount(p0, p1)
+}
+
+func (s *ManagerStub) GetAccount(p0 context.Context, p1 string) (*types.Account, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ManagerStruct) GetDeploymentList(p0 context.Context, p1 *types.GetDeploymentOption) ([]*types.Deployment, error) {
 	if s.Internal.GetDeploymentList == nil {
 		return *new([]*types.Deployment), ErrNotSupported
@@ -239,6 +370,28 @@ func (s *ManagerStub) GetDeploymentList(p0 context.Context, p1 *types.GetDeploym
 	return *new([]*types.Deployment), ErrNotSupported
 }
 
+func (s *ManagerStruct) GetDeploymentMetrics(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	if s.Internal.GetDeploymentMetrics == nil {
+		return *new([]*types.ServiceMetrics), ErrNotSupported
+	}
+	return s.Internal.GetDeploymentMetrics(p0, p1)
+}
+
+func (s *ManagerStub) GetDeploymentMetrics(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	return *new([]*types.ServiceMetrics), ErrNotSupported
+}
+
+func (s *ManagerStruct) GetDeploymentRevisions(p0 context.Context, p1 types.DeploymentID) ([]*types.DeploymentRevision, error) {
+	if s.Internal.GetDeploymentRevisions == nil {
+		return *new([]*types.DeploymentRevision), ErrNotSupported
+	}
+	return s.Internal.GetDeploymentRevisions(p0, p1)
+}
+
+func (s *ManagerStub) GetDeploymentRevisions(p0 context.Context, p1 types.DeploymentID) ([]*types.DeploymentRevision, error) {
+	return *new([]*types.DeploymentRevision), ErrNotSupported
+}
+
 func (s *ManagerStruct) GetEvents(p0 context.Context, p1 *types.Deployment) ([]*types.ServiceEvent, error) {
 	if s.Internal.GetEvents == nil {
 		return *new([]*types.ServiceEvent), ErrNotSupported
@@ -250,6 +403,17 @@ func (s *ManagerStub) GetEvents(p0 context.Context, p1 *types.Deployment) ([]*ty
 	return *new([]*types.ServiceEvent), ErrNotSupported
 }
 
+func (s *ManagerStruct) GetLedger(p0 context.Context, p1 *types.GetLedgerOption) ([]*types.LedgerEntry, error) {
+	if s.Internal.GetLedger == nil {
+		return *new([]*types.LedgerEntry), ErrNotSupported
+	}
+	return s.Internal.GetLedger(p0, p1)
+}
+
+func (s *ManagerStub) GetLedger(p0 context.Context, p1 *types.GetLedgerOption) ([]*types.LedgerEntry, error) {
+	return *new([]*types.LedgerEntry), ErrNotSupported
+}
+
 func (s *ManagerStruct) GetLogs(p0 context.Context, p1 *types.Deployment) ([]*types.ServiceLog, error) {
 	if s.Internal.GetLogs == nil {
 		return *new([]*types.ServiceLog), ErrNotSupported
@@ -294,6 +458,50 @@ func (s *ManagerStub) ProviderConnect(p0 context.Context, p1 string, p2 *types.P
 	return ErrNotSupported
 }
 
+func (s *ManagerStruct) RenewDeployment(p0 context.Context, p1 types.DeploymentID, p2 time.Duration) error {
+	if s.Internal.RenewDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.RenewDeployment(p0, p1, p2)
+}
+
+func (s *ManagerStub) RenewDeployment(p0 context.Context, p1 types.DeploymentID, p2 time.Duration) error {
+	return ErrNotSupported
+}
+
+func (s *ManagerStruct) RestartDeployment(p0 context.Context, p1 types.DeploymentID, p2 string) error {
+	if s.Internal.RestartDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.RestartDeployment(p0, p1, p2)
+}
+
+func (s *ManagerStub) RestartDeployment(p0 context.Context, p1 types.DeploymentID, p2 string) error {
+	return ErrNotSupported
+}
+
+func (s *ManagerStruct) RollbackDeployment(p0 context.Context, p1 types.DeploymentID, p2 int64, p3 *types.Signature) error {
+	if s.Internal.RollbackDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.RollbackDeployment(p0, p1, p2, p3)
+}
+
+func (s *ManagerStub) RollbackDeployment(p0 context.Context, p1 types.DeploymentID, p2 int64, p3 *types.Signature) error {
+	return ErrNotSupported
+}
+
+func (s *ManagerStruct) ScaleDeployment(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error {
+	if s.Internal.ScaleDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.ScaleDeployment(p0, p1, p2, p3)
+}
+
+func (s *ManagerStub) ScaleDeployment(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error {
+	return ErrNotSupported
+}
+
 func (s *ManagerStruct) SetProperties(p0 context.Context, p1 *types.Properties) error {
 	if s.Internal.SetProperties == nil {
 		return ErrNotSupported
@@ -305,6 +513,17 @@ func (s *ManagerStub) SetProperties(p0 context.Context, p1 *types.Properties) er
 	return ErrNotSupported
 }
 
+func (s *ManagerStruct) StreamLogs(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) {
+	if s.Internal.StreamLogs == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.StreamLogs(p0, p1, p2)
+}
+
+func (s *ManagerStub) StreamLogs(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ManagerStruct) UpdateDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.UpdateDeployment == nil {
 		return ErrNotSupported
@@ -316,6 +535,17 @@ func (s *ManagerStub) UpdateDeployment(p0 context.Context, p1 *types.Deployment)
 	return ErrNotSupported
 }
 
+func (s *ManagerStruct) WatchEvents(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	if s.Internal.WatchEvents == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.WatchEvents(p0, p1)
+}
+
+func (s *ManagerStub) WatchEvents(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ProviderStruct) CloseDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.CloseDeployment == nil {
 		return ErrNotSupported
@@ -327,6 +557,28 @@ func (s *ProviderStub) CloseDeployment(p0 context.Context, p1 *types.Deployment)
 	return ErrNotSupported
 }
 
+func (s *ProviderStruct) CopyFromDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) {
+	if s.Internal.CopyFromDeployment == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.CopyFromDeployment(p0, p1, p2)
+}
+
+func (s *ProviderStub) CopyFromDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions) (<-chan types.ExecOutput, error) {
+	return nil, ErrNotSupported
+}
+
+func (s *ProviderStruct) CopyToDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error {
+	if s.Internal.CopyToDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.CopyToDeployment(p0, p1, p2, p3)
+}
+
+func (s *ProviderStub) CopyToDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.CopyOptions, p3 io.Reader) error {
+	return ErrNotSupported
+}
+
 func (s *ProviderStruct) CreateDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.CreateDeployment == nil {
 		return ErrNotSupported
@@ -338,6 +590,17 @@ func (s *ProviderStub) CreateDeployment(p0 context.Context, p1 *types.Deployment
 	return ErrNotSupported
 }
 
+func (s *ProviderStruct) ExecDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) {
+	if s.Internal.ExecDeployment == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.ExecDeployment(p0, p1, p2, p3)
+}
+
+func (s *ProviderStub) ExecDeployment(p0 context.Context, p1 types.DeploymentID, p2 types.ExecOptions, p3 io.Reader) (<-chan types.ExecOutput, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ProviderStruct) GetDeployment(p0 context.Context, p1 types.DeploymentID) (*types.Deployment, error) {
 	if s.Internal.GetDeployment == nil {
 		return nil, ErrNotSupported
@@ -349,6 +612,17 @@ func (s *ProviderStub) GetDeployment(p0 context.Context, p1 types.DeploymentID)
 	return nil, ErrNotSupported
 }
 
+func (s *ProviderStruct) GetDeploymentMetrics(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	if s.Internal.GetDeploymentMetrics == nil {
+		return *new([]*types.ServiceMetrics), ErrNotSupported
+	}
+	return s.Internal.GetDeploymentMetrics(p0, p1)
+}
+
+func (s *ProviderStub) GetDeploymentMetrics(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	return *new([]*types.ServiceMetrics), ErrNotSupported
+}
+
 func (s *ProviderStruct) GetEvents(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceEvent, error) {
 	if s.Internal.GetEvents == nil {
 		return *new([]*types.ServiceEvent), ErrNotSupported
@@ -360,6 +634,17 @@ func (s *ProviderStub) GetEvents(p0 context.Context, p1 types.DeploymentID) ([]*
 	return *new([]*types.ServiceEvent), ErrNotSupported
 }
 
+func (s *ProviderStruct) GetKubeSettings(p0 context.Context) (*types.KubeSettings, error) {
+	if s.Internal.GetKubeSettings == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.GetKubeSettings(p0)
+}
+
+func (s *ProviderStub) GetKubeSettings(p0 context.Context) (*types.KubeSettings, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ProviderStruct) GetLogs(p0 context.Context, p1 types.DeploymentID) ([]*types.ServiceLog, error) {
 	if s.Internal.GetLogs == nil {
 		return *new([]*types.ServiceLog), ErrNotSupported
@@ -382,6 +667,39 @@ func (s *ProviderStub) GetStatistics(p0 context.Context) (*types.ResourcesStatis
 	return nil, ErrNotSupported
 }
 
+func (s *ProviderStruct) ListDeploymentIDs(p0 context.Context) ([]types.DeploymentID, error) {
+	if s.Internal.ListDeploymentIDs == nil {
+		return *new([]types.DeploymentID), ErrNotSupported
+	}
+	return s.Internal.ListDeploymentIDs(p0)
+}
+
+func (s *ProviderStub) ListDeploymentIDs(p0 context.Context) ([]types.DeploymentID, error) {
+	return *new([]types.DeploymentID), ErrNotSupported
+}
+
+func (s *ProviderStruct) RestartDeployment(p0 context.Context, p1 types.DeploymentID, p2 string) error {
+	if s.Internal.RestartDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.RestartDeployment(p0, p1, p2)
+}
+
+func (s *ProviderStub) RestartDeployment(p0 context.Context, p1 types.DeploymentID, p2 string) error {
+	return ErrNotSupported
+}
+
+func (s *ProviderStruct) ScaleDeployment(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error {
+	if s.Internal.ScaleDeployment == nil {
+		return ErrNotSupported
+	}
+	return s.Internal.ScaleDeployment(p0, p1, p2, p3)
+}
+
+func (s *ProviderStub) ScaleDeployment(p0 context.Context, p1 types.DeploymentID, p2 string, p3 int) error {
+	return ErrNotSupported
+}
+
 func (s *ProviderStruct) Session(p0 context.Context) (uuid.UUID, error) {
 	if s.Internal.Session == nil {
 		return *new(uuid.UUID), ErrNotSupported
@@ -393,6 +711,17 @@ func (s *ProviderStub) Session(p0 context.Context) (uuid.UUID, error) {
 	return *new(uuid.UUID), ErrNotSupported
 }
 
+func (s *ProviderStruct) StreamLogs(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) {
+	if s.Internal.StreamLogs == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.StreamLogs(p0, p1, p2)
+}
+
+func (s *ProviderStub) StreamLogs(p0 context.Context, p1 types.DeploymentID, p2 types.LogOptions) (<-chan types.LogLine, error) {
+	return nil, ErrNotSupported
+}
+
 func (s *ProviderStruct) UpdateDeployment(p0 context.Context, p1 *types.Deployment) error {
 	if s.Internal.UpdateDeployment == nil {
 		return ErrNotSupported
@@ -415,6 +744,17 @@ func (s *ProviderStub) Version(p0 context.Context) (Version, error) {
 	return *new(Version), ErrNotSupported
 }
 
+func (s *ProviderStruct) WatchEvents(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	if s.Internal.WatchEvents == nil {
+		return nil, ErrNotSupported
+	}
+	return s.Internal.WatchEvents(p0, p1)
+}
+
+func (s *ProviderStub) WatchEvents(p0 context.Context, p1 types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	return nil, ErrNotSupported
+}
+
 var _ Common = new(CommonStruct)
 var _ Manager = new(ManagerStruct)
 var _ Provider = new(ProviderStruct)
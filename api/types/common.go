@@ -1,3 +1,12 @@
 package types
 
+import "github.com/filecoin-project/go-jsonrpc/auth"
+
 type OpenRPCDocument map[string]interface{}
+
+// JWTPayload is the content of an API token
+type JWTPayload struct {
+	Allow []auth.Permission
+	// the owner the token is restricted to, empty for tokens not bound to an owner
+	Owner string `json:",omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -47,6 +48,7 @@ type Deployment struct {
 	Version   []byte          `db:"version"`
 	Authority bool            `db:"authority"`
 	Services  []*Service
+	Signature *Signature `db:"signature"`
 
 	// Internal
 	Type             DeploymentType `db:"type"`
@@ -73,6 +75,37 @@ type Service struct {
 	Status       ReplicasStatus `db:"status"`
 	ErrorMessage string         `db:"error_message"`
 	Arguments    Arguments      `db:"arguments"`
+	// overrides the entrypoint of the image, Arguments are passed to it
+	Command Arguments `db:"command"`
+	// environment variables kept secret, their values are write only and masked in the responses
+	SecretEnv Env `db:"-"`
+	// SecretEnv sealed by the manager, internal
+	SealedSecretEnv []byte `db:"secret_env" json:"-"`
+	// number of pods running the service, defaults to 1
+	Replicas int `db:"replicas"`
+	// scale the service between bounds instead of running a fixed number of replicas
+	Autoscale *AutoscalePolicy `db:"autoscale"`
+	// health checks of the container
+	LivenessProbe  *Probe `db:"liveness_probe"`
+	ReadinessProbe *Probe `db:"readiness_probe"`
+	StartupProbe   *Probe `db:"startup_probe"`
+	// persistent volumes, they survive the restarts of the pods
+	Volumes Volumes `db:"volumes"`
+	// configuration files mounted in the container
+	Files Files `db:"files"`
+	// serve the http ports over https
+	TLS *TLSConfig `db:"tls"`
+	// TLS key sealed by the manager, internal
+	SealedTLSKey []byte `db:"tls_key" json:"-"`
+	// credentials of the private registry of the image. They are write only: the manager
+	// never returns them and keeps the current ones when an update has none.
+	Credentials *RegistryCredentials `db:"-"`
+	// Credentials sealed by the manager, internal
+	SealedCredentials []byte `db:"credentials" json:"-"`
+	// hostnames the http ports of the service are reachable at, reported by the provider
+	URIs []string `db:"-"`
+	// certificate of the hosts, reported by the provider when TLS is enabled
+	Certificate *CertificateStatus `db:"-"`
 	ComputeResources
 
 	// Internal
@@ -82,6 +115,10 @@ type Service struct {
 	UpdatedAt    time.Time    `db:"updated_at"`
 }
 
+// MaskedValue replaces the values of the secret environment variables in the responses.
+// An update keeps the current value of the variables set to it.
+const MaskedValue = "******"
+
 type Env map[string]string
 
 func (e Env) Value() (driver.Value, error) {
@@ -92,32 +129,181 @@ func (e Env) Value() (driver.Value, error) {
 	return json.Marshal(x)
 }
 
-func (e Env) Scan(value interface{}) error {
+func (e *Env) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
 	b, ok := value.([]byte)
 	if !ok {
 		return errors.New("type assertion to []byte failed")
 	}
-	if err := json.Unmarshal(b, &e); err != nil {
+	if err := json.Unmarshal(b, e); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Arguments is stored as a JSON array, the rows stored comma joined by older versions are still read
 type Arguments []string
 
 func (a Arguments) Value() (driver.Value, error) {
-	return strings.Join(a, ","), nil
+	x := make([]string, 0, len(a))
+	x = append(x, a...)
+	return json.Marshal(x)
 }
 
-func (a Arguments) Scan(value interface{}) error {
+func (a *Arguments) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
 	b, ok := value.([]byte)
 	if !ok {
 		return errors.New("type assertion to []byte failed")
 	}
-	copy(a, strings.Split(string(b), ","))
+	if len(b) == 0 {
+		return nil
+	}
+	if b[0] == '[' && json.Unmarshal(b, a) == nil {
+		return nil
+	}
+	*a = strings.Split(string(b), ",")
 	return nil
 }
 
+// MaxReplicas bounds the replicas of a service, and the max replicas of an autoscaled one
+const MaxReplicas = 100
+
+// AutoscalePolicy sets the bounds of the replicas of an autoscaled service, and the
+// average utilisation of the requested resources the replicas are scaled to
+type AutoscalePolicy struct {
+	MinReplicas int
+	MaxReplicas int
+	// target cpu utilisation in percent, not used when 0
+	TargetCPUUtilization int
+	// target memory utilisation in percent, not used when 0
+	TargetMemoryUtilization int
+}
+
+func (a AutoscalePolicy) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *AutoscalePolicy) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, a)
+}
+
+// Volume is a persistent volume mounted in the container of a service.
+// Every replica of the service gets its own volume.
+type Volume struct {
+	Name string
+	// size in MB, like the storage of the service
+	Size     int64
+	Mount    string
+	ReadOnly bool `json:",omitempty"`
+	// storage class of the provider cluster, its default class when empty
+	StorageClass string `json:",omitempty"`
+}
+
+type Volumes []Volume
+
+func (v Volumes) Value() (driver.Value, error) {
+	x := make([]Volume, 0, len(v))
+	x = append(x, v...)
+	return json.Marshal(x)
+}
+
+func (v *Volumes) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, v)
+}
+
+// File is a configuration file mounted read only in the container of a service
+type File struct {
+	// absolute path of the file in the container
+	Path    string
+	Content string
+	// permission bits of the file, 0644 when zero
+	Mode int32 `json:",omitempty"`
+}
+
+type Files []File
+
+func (f Files) Value() (driver.Value, error) {
+	x := make([]File, 0, len(f))
+	x = append(x, f...)
+	return json.Marshal(x)
+}
+
+func (f *Files) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, f)
+}
+
+// TLSConfig enables https on the hosts of a service. The certificate is issued by the
+// provider when the PEM encoded certificate and key are not supplied.
+type TLSConfig struct {
+	Certificate string `json:",omitempty"`
+	// write only: the manager never returns it and keeps the current one when an update has none
+	Key string `json:",omitempty"`
+}
+
+// Value never stores the key, it is sealed apart by the manager
+func (t TLSConfig) Value() (driver.Value, error) {
+	t.Key = ""
+	return json.Marshal(t)
+}
+
+func (t *TLSConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, t)
+}
+
+// RegistryCredentials authenticates the pulls of a private image
+type RegistryCredentials struct {
+	// registry host, like registry.example.com or https://index.docker.io/v1/ for docker hub
+	Server   string
+	Username string
+	// password or access token
+	Password string
+}
+
+// String hides the password from the logs
+func (c RegistryCredentials) String() string {
+	return fmt.Sprintf("{Server:%s Username:%s Password:[redacted]}", c.Server, c.Username)
+}
+
+type CertificateStatus struct {
+	Ready    bool
+	Hosts    []string  `json:",omitempty"`
+	NotAfter time.Time `json:",omitempty"`
+	Message  string    `json:",omitempty"`
+}
+
 type Protocol string
 
 const (
@@ -129,6 +315,26 @@ type Port struct {
 	Protocol   Protocol `db:"protocol"`
 	Port       int      `db:"port"`
 	ExposePort int      `db:"expose_port"`
+	// hostnames routed to a port 80 by the ingress of the provider,
+	// a hostname is generated when empty and the provider has an ingress domain
+	Hosts       []string     `db:"hosts"`
+	HTTPOptions *HTTPOptions `db:"http_options"`
+}
+
+// HTTPOptions tunes the proxying of the requests to an http port
+type HTTPOptions struct {
+	// max size of the request body in bytes
+	MaxBodySize uint32
+	// timeouts in milliseconds
+	ReadTimeout uint32
+	SendTimeout uint32
+	// number of upstreams the request is passed to before failing
+	NextTries uint32
+	// time in milliseconds a request is passed to next upstreams, 0 for no limit
+	NextTimeout uint32
+	// cases the request is passed to the next upstream: error, timeout, invalid_header,
+	// http_500, http_502, http_503, http_504, http_403, http_404, http_429 and off
+	NextCases []string
 }
 
 type Ports []Port
@@ -141,12 +347,15 @@ func (a Ports) Value() (driver.Value, error) {
 	return json.Marshal(x)
 }
 
-func (a Ports) Scan(value interface{}) error {
+func (a *Ports) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
 	b, ok := value.([]byte)
 	if !ok {
 		return errors.New("type assertion to []byte failed")
 	}
-	if err := json.Unmarshal(b, &a); err != nil {
+	if err := json.Unmarshal(b, a); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgumentsScan(t *testing.T) {
+	args := Arguments{"sh", "-c", "echo a,b"}
+	value, err := args.Value()
+	require.NoError(t, err)
+
+	var scanned Arguments
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, args, scanned)
+
+	// the arguments stored before they were encoded in JSON are separated by commas
+	var legacy Arguments
+	require.NoError(t, legacy.Scan([]byte("--port,8080")))
+	require.Equal(t, Arguments{"--port", "8080"}, legacy)
+
+	legacy = nil
+	require.NoError(t, legacy.Scan([]byte("[not json")))
+	require.Equal(t, Arguments{"[not json"}, legacy)
+
+	var empty Arguments
+	require.NoError(t, empty.Scan(nil))
+	require.NoError(t, empty.Scan([]byte{}))
+	require.Nil(t, empty)
+}
@@ -0,0 +1,30 @@
+package types
+
+import "time"
+
+// LedgerEntry records a debit of a deployment balance
+type LedgerEntry struct {
+	ID           int64        `db:"id"`
+	DeploymentID DeploymentID `db:"deployment_id"`
+	Owner        string       `db:"owner"`
+	Amount       float64      `db:"amount"`
+	Balance      float64      `db:"balance"`
+	// the billed period
+	StartTime time.Time `db:"start_time"`
+	EndTime   time.Time `db:"end_time"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type GetLedgerOption struct {
+	Owner        string
+	DeploymentID DeploymentID
+	Page         int
+	Size         int
+}
+
+// Account holds the credit of an owner, the balances of its deployments are drawn from it
+type Account struct {
+	Owner     string    `db:"owner"`
+	Balance   float64   `db:"balance"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -6,3 +6,55 @@ type ServiceLog struct {
 	ServiceName string
 	Logs        []Log
 }
+
+// LogOptions selects the logs to stream
+type LogOptions struct {
+	// only stream the logs of this service, all services when empty
+	Service string
+	// keep streaming the new logs
+	Follow bool
+	// number of lines from the end of the logs to start with, all lines when 0
+	TailLines int64
+	// only return logs newer than this many seconds, all logs when 0
+	SinceSeconds int64
+	// prefix each line with its timestamp
+	Timestamps bool
+	// return the logs of the previous terminated container
+	Previous bool
+}
+
+// LogLine is a line of the output of a service
+type LogLine struct {
+	ServiceName string
+	PodName     string
+	Line        Log
+}
+
+// ExecOptions describes a command to run in a pod of a service
+type ExecOptions struct {
+	// service to run the command in, may be empty when the deployment has a single service
+	Service string
+	Command []string
+	// attach the stdin of the command
+	Stdin bool
+	// allocate a terminal, the stderr is then merged into the stdout
+	TTY bool
+}
+
+// ExecOutput is a chunk of the output of a command. The last message of the
+// stream has Exited set, along with the exit code or the error of the command.
+type ExecOutput struct {
+	Stdout   []byte `json:",omitempty"`
+	Stderr   []byte `json:",omitempty"`
+	Exited   bool   `json:",omitempty"`
+	ExitCode int    `json:",omitempty"`
+	Error    string `json:",omitempty"`
+}
+
+// CopyOptions locates files in a pod of a service
+type CopyOptions struct {
+	// service to copy the files from or to, may be empty when the deployment has a single service
+	Service string
+	// path in the container, the existing directory to extract into when copying to the deployment
+	Path string
+}
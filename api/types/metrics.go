@@ -0,0 +1,26 @@
+package types
+
+import "time"
+
+// PodMetrics is the resource usage of a pod
+type PodMetrics struct {
+	PodName string
+	// cpu usage in millicores
+	CPU int64
+	// memory working set in bytes
+	Memory   int64
+	Restarts int32
+	// when the usage was sampled, zero when the metrics server has no sample yet
+	Timestamp time.Time
+}
+
+// ServiceMetrics is the resource usage of the pods of a service
+type ServiceMetrics struct {
+	ServiceName string
+	// total cpu usage of the pods in millicores
+	CPU int64
+	// total memory working set of the pods in bytes
+	Memory   int64
+	Restarts int32
+	Pods     []*PodMetrics
+}
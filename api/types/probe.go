@@ -0,0 +1,54 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Probe checks the health of the container of a service with exactly one of
+// HTTPGet, TCPSocket or Exec. The timing fields use the kubernetes defaults when 0.
+type Probe struct {
+	HTTPGet   *HTTPGetProbe   `json:",omitempty"`
+	TCPSocket *TCPSocketProbe `json:",omitempty"`
+	Exec      *ExecProbe      `json:",omitempty"`
+
+	InitialDelaySeconds int32 `json:",omitempty"`
+	PeriodSeconds       int32 `json:",omitempty"`
+	TimeoutSeconds      int32 `json:",omitempty"`
+	SuccessThreshold    int32 `json:",omitempty"`
+	FailureThreshold    int32 `json:",omitempty"`
+}
+
+// HTTPGetProbe succeeds when a GET of the path answers with a status between 200 and 399
+type HTTPGetProbe struct {
+	Path string
+	Port int
+	// HTTP or HTTPS, defaults to HTTP
+	Scheme string `json:",omitempty"`
+}
+
+// TCPSocketProbe succeeds when a connection to the port can be opened
+type TCPSocketProbe struct {
+	Port int
+}
+
+// ExecProbe succeeds when the command exits with 0 in the container
+type ExecProbe struct {
+	Command []string
+}
+
+func (p Probe) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *Probe) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, p)
+}
@@ -35,6 +35,14 @@ type Provider struct {
 	UpdatedAt time.Time     `db:"updated_at"`
 }
 
+// ProviderStateHistory records a liveness transition of a provider
+type ProviderStateHistory struct {
+	ID         int64         `db:"id"`
+	ProviderID ProviderID    `db:"provider_id"`
+	State      ProviderState `db:"state"`
+	CreatedAt  time.Time     `db:"created_at"`
+}
+
 type GetProviderOption struct {
 	Owner string
 	ID    ProviderID
@@ -69,3 +77,20 @@ type Storage struct {
 	Active     uint64
 	Pending    uint64
 }
+
+// KubeSettings is the configuration of the kubernetes objects the provider generates for the deployments
+type KubeSettings struct {
+	ServiceType            string
+	IngressStaticHosts     bool
+	IngressDomain          string
+	IngressExposeLBHosts   bool
+	IngressClusterIssuer   string
+	ClusterPublicHostname  string
+	NetworkPoliciesEnabled bool
+	CPUCommitLevel         float64
+	GPUCommitLevel         float64
+	MemoryCommitLevel      float64
+	StorageCommitLevel     float64
+	RuntimeClass           string
+	ImagePullSecretsName   string
+}
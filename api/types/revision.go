@@ -0,0 +1,39 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DeploymentRevision is an immutable snapshot of the services of a deployment, recorded on every change
+type DeploymentRevision struct {
+	ID           int64        `db:"id"`
+	DeploymentID DeploymentID `db:"deployment_id"`
+	Revision     int64        `db:"revision"`
+	Author       string       `db:"author"`
+	Services     ServiceSpecs `db:"services"`
+	Summary      string       `db:"summary"`
+	CreatedAt    time.Time    `db:"created_at"`
+}
+
+// ServiceSpecs is the services of a revision, stored as json
+type ServiceSpecs []*Service
+
+func (s ServiceSpecs) Value() (driver.Value, error) {
+	x := make([]*Service, 0, len(s))
+	x = append(x, s...)
+	return json.Marshal(x)
+}
+
+func (s *ServiceSpecs) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, s)
+}
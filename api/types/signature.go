@@ -0,0 +1,96 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SignAction is the operation a signature authorizes
+type SignAction string
+
+const (
+	SignActionCreate SignAction = "create"
+	SignActionUpdate SignAction = "update"
+	SignActionClose  SignAction = "close"
+	// SignActionRollback signs the revision a deployment is rolled back to
+	SignActionRollback SignAction = "rollback"
+)
+
+// Signature proves the owner of a deployment requested an action on it
+type Signature struct {
+	// secp256k1 or ed25519
+	KeyType   string
+	PubKey    []byte
+	Signature []byte
+	Action    SignAction
+	SignedAt  time.Time
+	// random value identifying the signed request, it is accepted only once
+	Nonce string
+	// the revision a rollback is signed for
+	Revision int64 `json:",omitempty"`
+}
+
+func (s Signature) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *Signature) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, s)
+}
+
+// DeploymentSignBytes returns the canonical encoding of the deployment signed by its owner for the
+// action of the signature. Only the fields set by the owner are signed: the id of a created deployment
+// is assigned by the manager, closing or rolling back a deployment only signs its id and owner.
+func DeploymentSignBytes(deployment *Deployment, sig *Signature) ([]byte, error) {
+	doc := Deployment{
+		ID:    deployment.ID,
+		Owner: deployment.Owner,
+	}
+
+	if sig.Action == SignActionCreate {
+		doc.ID = ""
+	}
+
+	if sig.Action == SignActionCreate || sig.Action == SignActionUpdate {
+		doc.Name = deployment.Name
+		doc.Type = deployment.Type
+		doc.Authority = deployment.Authority
+		doc.Balance = deployment.Balance
+		doc.ProviderID = deployment.ProviderID
+		doc.Expiration = deployment.Expiration.UTC()
+
+		for _, service := range deployment.Services {
+			s := *service
+			s.Status = ReplicasStatus{}
+			s.ErrorMessage = ""
+			s.ID = 0
+			s.DeploymentID = ""
+			s.CreatedAt = time.Time{}
+			s.UpdatedAt = time.Time{}
+			doc.Services = append(doc.Services, &s)
+		}
+	}
+
+	return json.Marshal(struct {
+		Action     SignAction
+		SignedAt   time.Time
+		Nonce      string
+		Revision   int64
+		Deployment Deployment
+	}{
+		Action:     sig.Action,
+		SignedAt:   sig.SignedAt.UTC(),
+		Nonce:      sig.Nonce,
+		Revision:   sig.Revision,
+		Deployment: doc,
+	})
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+var accountCmds = &cli.Command{
+	Name:  "account",
+	Usage: "Manage the credit of the deployment owners",
+	Subcommands: []*cli.Command{
+		AccountShow,
+		AccountAddCredit,
+	},
+}
+
+var AccountShow = &cli.Command{
+	Name:      "show",
+	Usage:     "show the credit of an owner, the one of the caller by default",
+	ArgsUsage: "[owner]",
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		account, err := api.GetAccount(ctx, cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Owner:\t%s\n", account.Owner)
+		fmt.Printf("Balance:\t%f\n", account.Balance)
+		return nil
+	},
+}
+
+var AccountAddCredit = &cli.Command{
+	Name:      "add-credit",
+	Usage:     "add credit to an owner, the balances of its deployments are drawn from it",
+	ArgsUsage: "[owner] [amount]",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 2 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		amount, err := strconv.ParseFloat(cctx.Args().Get(1), 64)
+		if err != nil {
+			return err
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		return api.AddCredit(ctx, cctx.Args().First(), amount)
+	},
+}
@@ -30,6 +30,10 @@ var AuthCreateAdminToken = &cli.Command{
 			Name:  "perm",
 			Usage: "permission to assign to the token, one of: web, provider,admin",
 		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "restrict the token to the deployments of the owner",
+		},
 	},
 
 	Action: func(cctx *cli.Context) error {
@@ -57,7 +61,7 @@ var AuthCreateAdminToken = &cli.Command{
 			return fmt.Errorf("--perm flag has to be one of: %s", api.AllPermissions)
 		}
 
-		token, err := napi.AuthNew(ctx, api.AllPermissions[:idx])
+		token, err := napi.AuthNewOwner(ctx, api.AllPermissions[:idx], cctx.String("owner"))
 		if err != nil {
 			return err
 		}
@@ -77,6 +81,10 @@ var AuthAPIInfoToken = &cli.Command{
 			Name:  "perm",
 			Usage: "permission to assign to the token, one of: web, provider, admin, web",
 		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "restrict the token to the deployments of the owner",
+		},
 	},
 
 	Action: func(cctx *cli.Context) error {
@@ -104,7 +112,7 @@ var AuthAPIInfoToken = &cli.Command{
 			return fmt.Errorf("--perm flag has to be one of: %s", api.AllPermissions)
 		}
 
-		token, err := napi.AuthNew(ctx, api.AllPermissions[:idx])
+		token, err := napi.AuthNewOwner(ctx, api.AllPermissions[:idx], cctx.String("owner"))
 		if err != nil {
 			return err
 		}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/lib/execstream"
+	"github.com/Filecoin-Titan/titan-container/lib/signature"
 	"github.com/Filecoin-Titan/titan-container/lib/tablewriter"
+	nodetypes "github.com/Filecoin-Titan/titan-container/node/types"
 	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 	"sigs.k8s.io/yaml"
 )
 
@@ -26,17 +32,33 @@ var deploymentCmds = &cli.Command{
 		DeploymentList,
 		DeleteDeployment,
 		StatusDeployment,
+		RenewDeployment,
+		ScaleDeployment,
+		RestartDeployment,
+		DeploymentLedger,
+		NewDeploymentKey,
+		DeploymentHistory,
+		RollbackDeployment,
+		DeploymentEvents,
+		DeploymentLogs,
+		DeploymentTop,
+		ExecDeployment,
+		CopyDeployment,
 	},
 }
 
+var keyFileFlag = &cli.StringFlag{
+	Name:  "key-file",
+	Usage: "sign the request with the owner key stored in the file",
+}
+
 var CreateDeployment = &cli.Command{
 	Name:  "create",
 	Usage: "create new deployment",
 	Flags: []cli.Flag{
 		&cli.StringFlag{
-			Name:     "provider-id",
-			Usage:    "the provider id",
-			Required: true,
+			Name:  "provider-id",
+			Usage: "the provider id, selected by the manager when not set",
 		},
 		&cli.StringFlag{
 			Name:  "owner",
@@ -78,10 +100,73 @@ var CreateDeployment = &cli.Command{
 			Name:  "env",
 			Usage: "set the deployment running environment",
 		},
-		&cli.StringSliceFlag{
+		&cli.StringFlag{
+			Name:  "secret-env",
+			Usage: "set the deployment environment kept secret, in the same format as env",
+		},
+		&cli.StringFlag{
+			Name:  "command",
+			Usage: "override the entrypoint of the image, as a JSON array: '[\"sh\", \"-c\"]'",
+		},
+		&cli.StringFlag{
 			Name:  "args",
-			Usage: "set the deployment running arguments",
+			Usage: "set the deployment running arguments, as a JSON array: '[\"--port\", \"8080\"]'",
+		},
+		&cli.IntFlag{
+			Name:  "replicas",
+			Usage: "number of pods running the service, the min replicas when autoscaled",
+			Value: 1,
+		},
+		&cli.IntFlag{
+			Name:  "max-replicas",
+			Usage: "autoscale the service up to this number of pods",
+		},
+		&cli.IntFlag{
+			Name:  "target-cpu",
+			Usage: "average cpu utilisation in percent the autoscaled pods are kept at",
+		},
+		&cli.IntFlag{
+			Name:  "target-memory",
+			Usage: "average memory utilisation in percent the autoscaled pods are kept at",
+		},
+		&cli.StringSliceFlag{
+			Name:  "host",
+			Usage: "hostname routed to the service when the port is 80",
+		},
+		&cli.BoolFlag{
+			Name:  "tls",
+			Usage: "serve the hosts over https with a certificate issued by the provider",
+		},
+		&cli.StringFlag{
+			Name:  "tls-cert",
+			Usage: "serve the hosts over https with the PEM encoded certificate file",
+		},
+		&cli.StringFlag{
+			Name:  "tls-key",
+			Usage: "the PEM encoded private key file of the certificate",
+		},
+		&cli.StringFlag{
+			Name:  "registry-server",
+			Usage: "private registry of the image, like registry.example.com",
+		},
+		&cli.StringFlag{
+			Name:  "registry-user",
+			Usage: "username of the private registry",
+		},
+		&cli.StringFlag{
+			Name:    "registry-password",
+			Usage:   "password or access token of the private registry",
+			EnvVars: []string{"TITAN_REGISTRY_PASSWORD"},
 		},
+		&cli.StringSliceFlag{
+			Name:  "volume",
+			Usage: "persistent volume as name:size:mount[:ro], the size in MB",
+		},
+		&cli.Float64Flag{
+			Name:  "balance",
+			Usage: "the balance paying for the deployment, drawn from the credit of the owner",
+		},
+		keyFileFlag,
 	},
 	Action: func(cctx *cli.Context) error {
 		api, closer, err := GetManagerAPI(cctx)
@@ -94,7 +179,7 @@ var CreateDeployment = &cli.Command{
 		providerID := types.ProviderID(cctx.String("provider-id"))
 
 		if cctx.String("template") != "" {
-			return createDeploymentFromTemplate(ctx, api, providerID, cctx.String("template"))
+			return createDeploymentFromTemplate(cctx, api, providerID, cctx.String("template"))
 		}
 
 		if cctx.String("image") == "" {
@@ -109,16 +194,41 @@ var CreateDeployment = &cli.Command{
 			}
 		}
 
+		var secretEnv types.Env
+		if cctx.String("secret-env") != "" {
+			err := json.Unmarshal([]byte(cctx.String("secret-env")), &secretEnv)
+			if err != nil {
+				return err
+			}
+		}
+
+		var command, args types.Arguments
+		if cctx.String("command") != "" {
+			err := json.Unmarshal([]byte(cctx.String("command")), &command)
+			if err != nil {
+				return errors.Errorf("invalid command: %v", err)
+			}
+		}
+		if cctx.String("args") != "" {
+			err := json.Unmarshal([]byte(cctx.String("args")), &args)
+			if err != nil {
+				return errors.Errorf("invalid args: %v", err)
+			}
+		}
+
 		deployment := &types.Deployment{
 			ProviderID: providerID,
+			Owner:      cctx.String("owner"),
 			Name:       cctx.String("name"),
 			Authority:  cctx.Bool("auth"),
+			Balance:    cctx.Float64("balance"),
 			Services: []*types.Service{
 				{
 					Image: cctx.String("image"),
 					Ports: []types.Port{
 						{
-							Port: cctx.Int("port"),
+							Port:  cctx.Int("port"),
+							Hosts: cctx.StringSlice("host"),
 						},
 					},
 					ComputeResources: types.ComputeResources{
@@ -127,16 +237,113 @@ var CreateDeployment = &cli.Command{
 						Storage: cctx.Int64("storage"),
 					},
 					Env:       env,
-					Arguments: cctx.StringSlice("args"),
+					SecretEnv: secretEnv,
+					Command:   command,
+					Arguments: args,
+					Replicas:  cctx.Int("replicas"),
 				},
 			},
 		}
 
+		if cctx.String("registry-server") != "" {
+			deployment.Services[0].Credentials = &types.RegistryCredentials{
+				Server:   cctx.String("registry-server"),
+				Username: cctx.String("registry-user"),
+				Password: cctx.String("registry-password"),
+			}
+		}
+
+		tls, err := tlsFromFlags(cctx)
+		if err != nil {
+			return err
+		}
+		deployment.Services[0].TLS = tls
+
+		for _, value := range cctx.StringSlice("volume") {
+			volume, err := parseVolume(value)
+			if err != nil {
+				return err
+			}
+			deployment.Services[0].Volumes = append(deployment.Services[0].Volumes, volume)
+		}
+
+		if cctx.Int("max-replicas") > 0 {
+			deployment.Services[0].Autoscale = &types.AutoscalePolicy{
+				MinReplicas:             cctx.Int("replicas"),
+				MaxReplicas:             cctx.Int("max-replicas"),
+				TargetCPUUtilization:    cctx.Int("target-cpu"),
+				TargetMemoryUtilization: cctx.Int("target-memory"),
+			}
+		}
+
+		err = signDeployment(cctx, deployment, types.SignActionCreate)
+		if err != nil {
+			return err
+		}
+
 		return api.CreateDeployment(ctx, deployment)
 	},
 }
 
-func createDeploymentFromTemplate(ctx context.Context, api api.Manager, providerID types.ProviderID, path string) error {
+func tlsFromFlags(cctx *cli.Context) (*types.TLSConfig, error) {
+	if cctx.String("tls-cert") == "" && cctx.String("tls-key") == "" {
+		if cctx.Bool("tls") {
+			return &types.TLSConfig{}, nil
+		}
+		return nil, nil
+	}
+
+	if cctx.String("tls-cert") == "" || cctx.String("tls-key") == "" {
+		return nil, errors.Errorf("both --tls-cert and --tls-key must be set")
+	}
+
+	cert, err := os.ReadFile(cctx.String("tls-cert"))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := os.ReadFile(cctx.String("tls-key"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TLSConfig{Certificate: string(cert), Key: string(key)}, nil
+}
+
+func certificateState(status *types.CertificateStatus) string {
+	if status == nil {
+		return ""
+	}
+	if !status.Ready {
+		return status.Message
+	}
+	return fmt.Sprintf("valid until %s", status.NotAfter.Format(defaultDateTimeLayout))
+}
+
+// parseVolume parses a volume given as name:size:mount[:ro]
+func parseVolume(value string) (types.Volume, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 3 || len(parts) > 4 {
+		return types.Volume{}, errors.Errorf("invalid volume %s, expected name:size:mount[:ro]", value)
+	}
+
+	size, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return types.Volume{}, errors.Errorf("invalid volume size %s: %v", parts[1], err)
+	}
+
+	volume := types.Volume{Name: parts[0], Size: size, Mount: parts[2]}
+	if len(parts) == 4 {
+		if parts[3] != "ro" {
+			return types.Volume{}, errors.Errorf("invalid volume option %s", parts[3])
+		}
+		volume.ReadOnly = true
+	}
+
+	return volume, nil
+}
+
+func createDeploymentFromTemplate(cctx *cli.Context, api api.Manager, providerID types.ProviderID, path string) error {
 	yamlFiles, err := os.ReadFile(path)
 	if err != nil {
 		return err
@@ -148,8 +355,57 @@ func createDeploymentFromTemplate(ctx context.Context, api api.Manager, provider
 		return err
 	}
 
-	deployment.ProviderID = providerID
-	return api.CreateDeployment(ctx, &deployment)
+	if providerID != "" {
+		deployment.ProviderID = providerID
+	}
+
+	if cctx.String("owner") != "" {
+		deployment.Owner = cctx.String("owner")
+	}
+
+	err = signDeployment(cctx, &deployment, types.SignActionCreate)
+	if err != nil {
+		return err
+	}
+
+	return api.CreateDeployment(ReqContext(cctx), &deployment)
+}
+
+// signDeployment signs the deployment with the key given by the key-file flag, the owner defaults to the key address
+func signDeployment(cctx *cli.Context, deployment *types.Deployment, action types.SignAction) error {
+	key, err := ownerKey(cctx, deployment)
+	if err != nil || key == nil {
+		return err
+	}
+
+	return signature.SignDeployment(*key, deployment, action)
+}
+
+// ownerKey reads the key given by the key-file flag, it returns nil when the flag is not set
+func ownerKey(cctx *cli.Context, deployment *types.Deployment) (*nodetypes.KeyInfo, error) {
+	if cctx.String("key-file") == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(cctx.String("key-file"))
+	if err != nil {
+		return nil, err
+	}
+
+	var key nodetypes.KeyInfo
+	err = json.Unmarshal(b, &key)
+	if err != nil {
+		return nil, errors.Errorf("decoding key file: %v", err)
+	}
+
+	if deployment.Owner == "" {
+		deployment.Owner, err = signature.Address(key, signature.DefaultAddressPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &key, nil
 }
 
 var DeploymentList = &cli.Command{
@@ -201,6 +457,8 @@ var DeploymentList = &cli.Command{
 			tablewriter.Col("Storage"),
 			tablewriter.Col("Provider"),
 			tablewriter.Col("Port"),
+			tablewriter.Col("URIs"),
+			tablewriter.Col("TLS"),
 			tablewriter.Col("CreatedTime"),
 		)
 
@@ -246,6 +504,8 @@ var DeploymentList = &cli.Command{
 					"Storage":     units.BytesSize(float64(service.Storage * units.MiB)),
 					"Provider":    deployment.ProviderExposeIP,
 					"Port":        strings.Join(exposePorts, " "),
+					"URIs":        strings.Join(service.URIs, " "),
+					"TLS":         certificateState(service.Certificate),
 					"CreatedTime": deployment.CreatedAt.Format(defaultDateTimeLayout),
 				}
 				tw.Write(m)
@@ -260,7 +520,9 @@ var DeploymentList = &cli.Command{
 var DeleteDeployment = &cli.Command{
 	Name:  "delete",
 	Usage: "delete deployment",
-	Flags: []cli.Flag{},
+	Flags: []cli.Flag{
+		keyFileFlag,
+	},
 	Action: func(cctx *cli.Context) error {
 		if cctx.NArg() != 1 {
 			return IncorrectNumArgs(cctx)
@@ -287,6 +549,11 @@ var DeleteDeployment = &cli.Command{
 		}
 
 		for _, deployment := range deployments {
+			err = signDeployment(cctx, deployment, types.SignActionClose)
+			if err != nil {
+				return err
+			}
+
 			err = api.CloseDeployment(ctx, deployment)
 			if err != nil {
 				log.Errorf("delete deployment failed: %v", err)
@@ -341,7 +608,11 @@ var StatusDeployment = &cli.Command{
 
 		fmt.Printf("DeploymentID:\t%s\n", deployment.ID)
 		fmt.Printf("State:\t\t%s\n", types.DeploymentStateString(deployment.State))
+		fmt.Printf("Revision:\t%s\n", deployment.Version)
 		fmt.Printf("CreadTime:\t%v\n", deployment.CreatedAt)
+		fmt.Printf("Expiration:\t%v\n", deployment.Expiration)
+		fmt.Printf("Balance:\t%f\n", deployment.Balance)
+		fmt.Printf("Cost:\t\t%f/h\n", deployment.Cost)
 		fmt.Printf("--------\nEvents:\n")
 
 		serviceEvents, err := api.GetEvents(ctx, deployment)
@@ -373,3 +644,675 @@ var StatusDeployment = &cli.Command{
 		return nil
 	},
 }
+
+var RenewDeployment = &cli.Command{
+	Name:      "renew",
+	Usage:     "extend the lease of a deployment",
+	ArgsUsage: "[deployment id]",
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:     "duration",
+			Usage:    "how long to extend the lease by, eg. 720h",
+			Required: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		return api.RenewDeployment(ctx, deploymentID, cctx.Duration("duration"))
+	},
+}
+
+var ScaleDeployment = &cli.Command{
+	Name:      "scale",
+	Usage:     "set the number of pods of a service of a deployment",
+	ArgsUsage: "[deployment id] [replicas]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "service",
+			Usage: "the service to scale, required when the deployment has several services",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 2 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		replicas, err := strconv.Atoi(cctx.Args().Get(1))
+		if err != nil {
+			return errors.Errorf("invalid replicas: %v", err)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		return api.ScaleDeployment(ctx, deploymentID, cctx.String("service"), replicas)
+	},
+}
+
+var RestartDeployment = &cli.Command{
+	Name:      "restart",
+	Usage:     "restart the pods of a deployment one by one, and wait for them to be ready",
+	ArgsUsage: "[deployment id]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "service",
+			Usage: "only restart this service",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		err = api.RestartDeployment(ctx, deploymentID, cctx.String("service"))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("restarted")
+		return nil
+	},
+}
+
+var DeploymentLedger = &cli.Command{
+	Name:  "ledger",
+	Usage: "show the debits of deployment balances",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "owner address",
+		},
+		&cli.StringFlag{
+			Name:  "id",
+			Usage: "the deployment id",
+		},
+		&cli.IntFlag{
+			Name:  "page",
+			Usage: "the page number",
+			Value: 1,
+		},
+		&cli.IntFlag{
+			Name:  "size",
+			Usage: "the page size",
+			Value: 10,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+
+		entries, err := api.GetLedger(ctx, &types.GetLedgerOption{
+			Owner:        cctx.String("owner"),
+			DeploymentID: types.DeploymentID(cctx.String("id")),
+			Page:         cctx.Int("page"),
+			Size:         cctx.Int("size"),
+		})
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("DeploymentID"),
+			tablewriter.Col("Owner"),
+			tablewriter.Col("Amount"),
+			tablewriter.Col("Balance"),
+			tablewriter.Col("StartTime"),
+			tablewriter.Col("EndTime"),
+		)
+
+		for _, entry := range entries {
+			tw.Write(map[string]interface{}{
+				"DeploymentID": entry.DeploymentID,
+				"Owner":        entry.Owner,
+				"Amount":       fmt.Sprintf("%f", entry.Amount),
+				"Balance":      fmt.Sprintf("%f", entry.Balance),
+				"StartTime":    entry.StartTime.Format(defaultDateTimeLayout),
+				"EndTime":      entry.EndTime.Format(defaultDateTimeLayout),
+			})
+		}
+
+		tw.Flush(os.Stdout)
+		return nil
+	},
+}
+
+var NewDeploymentKey = &cli.Command{
+	Name:      "new-key",
+	Usage:     "generate a key to sign deployment requests, and print its owner address",
+	ArgsUsage: "[key file]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "the key type, one of: secp256k1, ed25519",
+			Value: string(signature.KTSecp256k1),
+		},
+		&cli.StringFlag{
+			Name:  "prefix",
+			Usage: "the bech32 prefix of the owner address",
+			Value: signature.DefaultAddressPrefix,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		key, err := signature.GenerateKey(nodetypes.KeyType(cctx.String("type")))
+		if err != nil {
+			return err
+		}
+
+		address, err := signature.Address(key, cctx.String("prefix"))
+		if err != nil {
+			return err
+		}
+
+		b, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(cctx.Args().First(), b, 0o600)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(address)
+		return nil
+	},
+}
+
+var DeploymentHistory = &cli.Command{
+	Name:      "history",
+	Usage:     "show the revisions of a deployment",
+	ArgsUsage: "[deployment id]",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		revisions, err := api.GetDeploymentRevisions(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Revision"),
+			tablewriter.Col("Author"),
+			tablewriter.Col("CreatedTime"),
+			tablewriter.NewLineCol("Summary"),
+		)
+
+		for _, revision := range revisions {
+			tw.Write(map[string]interface{}{
+				"Revision":    revision.Revision,
+				"Author":      revision.Author,
+				"CreatedTime": revision.CreatedAt.Format(defaultDateTimeLayout),
+				"Summary":     revision.Summary,
+			})
+		}
+
+		tw.Flush(os.Stdout)
+		return nil
+	},
+}
+
+var RollbackDeployment = &cli.Command{
+	Name:      "rollback",
+	Usage:     "apply the services of an earlier revision to a deployment",
+	ArgsUsage: "[deployment id] [revision]",
+	Flags: []cli.Flag{
+		keyFileFlag,
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 2 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		revision, err := strconv.ParseInt(cctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return errors.Errorf("invalid revision: %v", err)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deployment := &types.Deployment{ID: types.DeploymentID(cctx.Args().First())}
+
+		key, err := ownerKey(cctx, deployment)
+		if err != nil {
+			return err
+		}
+
+		if key != nil {
+			err = signature.SignRollback(*key, deployment, revision)
+			if err != nil {
+				return err
+			}
+		}
+
+		return api.RollbackDeployment(ctx, deployment.ID, revision, deployment.Signature)
+	},
+}
+
+var DeploymentEvents = &cli.Command{
+	Name:      "events",
+	Usage:     "show the events of a deployment",
+	ArgsUsage: "[deployment id]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "watch",
+			Aliases: []string{"w"},
+			Usage:   "keep printing the new events",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		if !cctx.Bool("watch") {
+			serviceEvents, err := api.GetEvents(ctx, &types.Deployment{ID: deploymentID})
+			if err != nil {
+				return err
+			}
+
+			for _, sv := range serviceEvents {
+				for _, event := range sv.Events {
+					fmt.Printf("[%s]\t%s\n", sv.ServiceName, event)
+				}
+			}
+			return nil
+		}
+
+		events, err := api.WatchEvents(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+
+		for sv := range events {
+			for _, event := range sv.Events {
+				fmt.Printf("[%s]\t%s\n", sv.ServiceName, event)
+			}
+		}
+
+		return nil
+	},
+}
+
+var DeploymentLogs = &cli.Command{
+	Name:      "logs",
+	Usage:     "print the logs of a deployment",
+	ArgsUsage: "[deployment id]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "follow",
+			Aliases: []string{"f"},
+			Usage:   "keep printing the new logs",
+		},
+		&cli.Int64Flag{
+			Name:  "tail",
+			Usage: "number of lines to show from the end of the logs, all when 0",
+		},
+		&cli.StringFlag{
+			Name:  "service",
+			Usage: "only show the logs of the service",
+		},
+		&cli.DurationFlag{
+			Name:  "since",
+			Usage: "only show the logs newer than a relative duration, eg. 10m",
+		},
+		&cli.BoolFlag{
+			Name:  "timestamps",
+			Usage: "show the timestamp of each line",
+		},
+		&cli.BoolFlag{
+			Name:  "previous",
+			Usage: "show the logs of the previous terminated containers",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		lines, err := api.StreamLogs(ctx, deploymentID, types.LogOptions{
+			Service:      cctx.String("service"),
+			Follow:       cctx.Bool("follow"),
+			TailLines:    cctx.Int64("tail"),
+			SinceSeconds: int64(cctx.Duration("since").Seconds()),
+			Timestamps:   cctx.Bool("timestamps"),
+			Previous:     cctx.Bool("previous"),
+		})
+		if err != nil {
+			return err
+		}
+
+		for line := range lines {
+			if cctx.String("service") != "" {
+				fmt.Println(line.Line)
+				continue
+			}
+			fmt.Printf("[%s]\t%s\n", line.ServiceName, line.Line)
+		}
+
+		return nil
+	},
+}
+
+var DeploymentTop = &cli.Command{
+	Name:      "top",
+	Usage:     "show the cpu and memory usage of a deployment",
+	ArgsUsage: "[deployment id]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "pods",
+			Usage: "show the usage of every pod instead of the services",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		deploymentID := types.DeploymentID(cctx.Args().First())
+
+		services, err := api.GetDeploymentMetrics(ctx, deploymentID)
+		if err != nil {
+			return err
+		}
+
+		podCol := "Pods"
+		if cctx.Bool("pods") {
+			podCol = "Pod"
+		}
+
+		tw := tablewriter.New(
+			tablewriter.Col("Service"),
+			tablewriter.Col(podCol),
+			tablewriter.Col("CPU"),
+			tablewriter.Col("Memory"),
+			tablewriter.Col("Restarts"),
+		)
+
+		for _, service := range services {
+			if !cctx.Bool("pods") {
+				tw.Write(map[string]interface{}{
+					"Service":  service.ServiceName,
+					"Pods":     len(service.Pods),
+					"CPU":      fmt.Sprintf("%dm", service.CPU),
+					"Memory":   units.BytesSize(float64(service.Memory)),
+					"Restarts": service.Restarts,
+				})
+				continue
+			}
+
+			for _, pod := range service.Pods {
+				tw.Write(map[string]interface{}{
+					"Service":  service.ServiceName,
+					"Pod":      pod.PodName,
+					"CPU":      fmt.Sprintf("%dm", pod.CPU),
+					"Memory":   units.BytesSize(float64(pod.Memory)),
+					"Restarts": pod.Restarts,
+				})
+			}
+		}
+
+		tw.Flush(os.Stdout)
+		return nil
+	},
+}
+
+var ExecDeployment = &cli.Command{
+	Name:      "exec",
+	Usage:     "run a command in a service of a deployment",
+	ArgsUsage: "[deployment id] -- [command] [args...]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "service",
+			Usage: "run the command in this service, required when the deployment has several services",
+		},
+		&cli.BoolFlag{
+			Name:    "stdin",
+			Aliases: []string{"i"},
+			Usage:   "pass the stdin to the command",
+		},
+		&cli.BoolFlag{
+			Name:    "tty",
+			Aliases: []string{"t"},
+			Usage:   "allocate a terminal for the command",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() < 2 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx, cancel := context.WithCancel(ReqContext(cctx))
+		defer cancel()
+
+		fd := int(os.Stdin.Fd())
+		opts := types.ExecOptions{
+			Service: cctx.String("service"),
+			Command: cctx.Args().Tail(),
+			Stdin:   cctx.Bool("stdin"),
+			TTY:     cctx.Bool("tty") && term.IsTerminal(fd),
+		}
+
+		pr, pw := io.Pipe()
+		stdin := execstream.NewWriter(pw)
+
+		outputs, err := api.ExecDeployment(ctx, types.DeploymentID(cctx.Args().First()), opts, pr)
+		if err != nil {
+			return err
+		}
+
+		if opts.TTY {
+			state, err := term.MakeRaw(fd)
+			if err != nil {
+				return err
+			}
+			defer term.Restore(fd, state) //nolint:errcheck
+
+			resize := func() {
+				width, height, err := term.GetSize(fd)
+				if err != nil {
+					return
+				}
+				_ = stdin.Resize(execstream.TerminalSize{Width: uint16(width), Height: uint16(height)})
+			}
+			resize()
+			notifyTerminalResize(ctx, resize)
+		}
+
+		if opts.Stdin {
+			go func() {
+				_, _ = io.Copy(stdin, os.Stdin)
+				_ = stdin.Close()
+			}()
+		} else {
+			_ = stdin.Close()
+		}
+
+		for output := range outputs {
+			if len(output.Stdout) > 0 {
+				_, _ = os.Stdout.Write(output.Stdout)
+			}
+			if len(output.Stderr) > 0 {
+				_, _ = os.Stderr.Write(output.Stderr)
+			}
+
+			if !output.Exited {
+				continue
+			}
+
+			if output.Error != "" {
+				return errors.New(output.Error)
+			}
+			if output.ExitCode != 0 {
+				return cli.Exit("", output.ExitCode)
+			}
+			return nil
+		}
+
+		return errors.New("connection closed before the command exited")
+	},
+}
+
+var CopyDeployment = &cli.Command{
+	Name:  "cp",
+	Usage: "copy files between the local machine and a service of a deployment",
+	UsageText: "deployment cp [command options] [local path] [deployment id]:[directory]\n" +
+		"deployment cp [command options] [deployment id]:[path] [local directory]",
+	Description: "The tar command must be available in the service container.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "service",
+			Usage: "copy from or to this service, required when the deployment has several services",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 2 {
+			return IncorrectNumArgs(cctx)
+		}
+
+		api, closer, err := GetManagerAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		ctx := ReqContext(cctx)
+		src, dst := cctx.Args().Get(0), cctx.Args().Get(1)
+
+		if id, remotePath, ok := parseRemotePath(dst); ok {
+			pr, pw := io.Pipe()
+			go func() {
+				pw.CloseWithError(writeTar(pw, src))
+			}()
+
+			return api.CopyToDeployment(ctx, id, types.CopyOptions{Service: cctx.String("service"), Path: remotePath}, pr)
+		}
+
+		id, remotePath, ok := parseRemotePath(src)
+		if !ok {
+			return errors.New("one of the paths must be [deployment id]:[path]")
+		}
+
+		outputs, err := api.CopyFromDeployment(ctx, id, types.CopyOptions{Service: cctx.String("service"), Path: remotePath})
+		if err != nil {
+			return err
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			for output := range outputs {
+				if len(output.Stdout) > 0 {
+					if _, err := pw.Write(output.Stdout); err != nil {
+						return
+					}
+				}
+
+				if output.Exited {
+					if output.Error != "" {
+						pw.CloseWithError(errors.New(output.Error))
+					} else {
+						pw.Close()
+					}
+					return
+				}
+			}
+			pw.CloseWithError(errors.New("connection closed before the copy completed"))
+		}()
+
+		err = extractTar(pr, dst)
+		pr.CloseWithError(err)
+		return err
+	},
+}
+
+// parseRemotePath splits a [deployment id]:[path] argument
+func parseRemotePath(arg string) (types.DeploymentID, string, bool) {
+	id, path, ok := strings.Cut(arg, ":")
+	// single letters are windows drives
+	if !ok || len(id) < 2 || strings.ContainsAny(id, `/\`) {
+		return "", "", false
+	}
+	return types.DeploymentID(id), path, true
+}
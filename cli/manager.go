@@ -8,4 +8,5 @@ import (
 var ManagerCMDs = []*cli.Command{
 	WithCategory("provider", providerCmds),
 	WithCategory("deployment", deploymentCmds),
+	WithCategory("account", accountCmds),
 }
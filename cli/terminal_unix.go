@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyTerminalResize calls resize every time the terminal window changes size
+func notifyTerminalResize(ctx context.Context, resize func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGWINCH)
+
+	go func() {
+		defer signal.Stop(sigChan)
+		for {
+			select {
+			case <-sigChan:
+				resize()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,6 @@
+package cli
+
+import "context"
+
+// notifyTerminalResize is not supported on windows, the terminal keeps its initial size
+func notifyTerminalResize(ctx context.Context, resize func()) {}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -45,6 +46,7 @@ func main() {
 	local := []*cli.Command{
 		initCmd,
 		runCmd,
+		kubeSettingsCmd,
 	}
 	if AdvanceBlockCmd != nil {
 		local = append(local, AdvanceBlockCmd)
@@ -124,6 +126,31 @@ var initCmd = &cli.Command{
 	},
 }
 
+var kubeSettingsCmd = &cli.Command{
+	Name:  "kube-settings",
+	Usage: "Print the kube settings the deployments are generated with",
+	Action: func(cctx *cli.Context) error {
+		providerAPI, closer, err := lcli.GetProviderAPI(cctx)
+		if err != nil {
+			return err
+		}
+		defer closer()
+
+		settings, err := providerAPI.GetKubeSettings(lcli.ReqContext(cctx))
+		if err != nil {
+			return err
+		}
+
+		out, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
 var runCmd = &cli.Command{
 	Name:  "run",
 	Usage: "Start provider service",
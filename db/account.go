@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/pkg/errors"
+)
+
+var ErrInsufficientCredit = errors.New("insufficient credit")
+
+// CreditAccount adds the amount to the credit of the owner
+func (m *ManagerDB) CreditAccount(ctx context.Context, owner string, amount float64, at time.Time) error {
+	qry := `INSERT INTO accounts (owner, balance, updated_at) VALUES (?, ?, ?) 
+		        ON DUPLICATE KEY UPDATE balance = balance + VALUES(balance), updated_at = VALUES(updated_at)`
+	_, err := m.db.ExecContext(ctx, qry, owner, amount, at)
+	return err
+}
+
+// DebitAccount withdraws the amount from the credit of the owner, ErrInsufficientCredit is
+// returned when the credit does not cover it
+func (m *ManagerDB) DebitAccount(ctx context.Context, owner string, amount float64, at time.Time) error {
+	qry := `UPDATE accounts SET balance = balance - ?, updated_at = ? WHERE owner = ? AND balance >= ?`
+	result, err := m.db.ExecContext(ctx, qry, amount, at, owner, amount)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrInsufficientCredit
+	}
+	return nil
+}
+
+// GetAccount returns the credit of the owner, an empty account when the owner has none
+func (m *ManagerDB) GetAccount(ctx context.Context, owner string) (*types.Account, error) {
+	var out types.Account
+	err := m.db.GetContext(ctx, &out, `SELECT * FROM accounts WHERE owner = ?`, owner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &types.Account{Owner: owner}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -28,6 +28,11 @@ func SqlDB(dsn string) (*sqlx.DB, error) {
 		return nil, errors.Errorf("failed to init db: %v", err)
 	}
 
+	err = migrateColumns(context.Background(), client)
+	if err != nil {
+		return nil, errors.Errorf("failed to migrate db: %v", err)
+	}
+
 	return client, nil
 }
 
@@ -35,7 +40,7 @@ func SqlDB(dsn string) (*sqlx.DB, error) {
 var createMainDBSQL embed.FS
 
 func createAllTables(ctx context.Context, mainDB *sqlx.DB) error {
-	fileNames := []string{"providers", "deployments", "services", "properties"}
+	fileNames := []string{"providers", "deployments", "services", "properties", "provider_state_history", "deployment_ledger", "deployment_revisions", "accounts", "signature_nonces"}
 
 	for _, fileName := range fileNames {
 		content, _ := createMainDBSQL.ReadFile("sql/" + fileName + ".sql")
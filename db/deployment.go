@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/jmoiron/sqlx"
 )
 
-func (m *ManagerDB) CreateDeployment(ctx context.Context, deployment *types.Deployment) error {
+// CreateDeployment saves the deployment, replacing its services, and records the revision
+func (m *ManagerDB) CreateDeployment(ctx context.Context, deployment *types.Deployment, revision *types.DeploymentRevision) error {
 	tx, err := m.db.Beginx()
 	if err != nil {
 		return err
@@ -22,26 +24,42 @@ func (m *ManagerDB) CreateDeployment(ctx context.Context, deployment *types.Depl
 		return err
 	}
 
-	err = addNewServices(ctx, tx, deployment.Services)
+	_, err = tx.ExecContext(ctx, `DELETE FROM services WHERE deployment_id = ?`, deployment.ID)
 	if err != nil {
 		return err
 	}
 
+	if len(deployment.Services) > 0 {
+		err = addNewServices(ctx, tx, deployment.Services)
+		if err != nil {
+			return err
+		}
+	}
+
+	if revision != nil {
+		err = addDeploymentRevision(ctx, tx, revision)
+		if err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
 func addNewDeployment(ctx context.Context, tx *sqlx.Tx, deployment *types.Deployment) error {
-	qry := `INSERT INTO deployments (id, name, owner, state, type, authority, version, balance, cost, expiration, provider_id, created_at, updated_at) 
-		        VALUES (:id, :name, :owner, :state, :type, :authority, :version, :balance, :cost, :expiration, :provider_id, :created_at, :updated_at)
-		         ON DUPLICATE KEY UPDATE  state=:state, authority=:authority, version=:version, balance=:balance, cost=:cost, expiration=:expiration, updated_at=:updated_at`
+	qry := `INSERT INTO deployments (id, name, owner, state, type, authority, version, balance, cost, expiration, provider_id, signature, created_at, updated_at) 
+		        VALUES (:id, :name, :owner, :state, :type, :authority, :version, :balance, :cost, :expiration, :provider_id, :signature, :created_at, :updated_at)
+		         ON DUPLICATE KEY UPDATE  state=:state, authority=:authority, version=:version, balance=:balance, cost=:cost, expiration=:expiration, signature=:signature, updated_at=:updated_at`
 	_, err := tx.NamedExecContext(ctx, qry, deployment)
 
 	return err
 }
 
 func addNewServices(ctx context.Context, tx *sqlx.Tx, services []*types.Service) error {
-	qry := `INSERT INTO services (id, name, image, ports, cpu, memory, storage, deployment_id, env, arguments, error_message, created_at, updated_at) 
-		        VALUES (:id,:name, :image, :ports, :cpu, :memory, :storage, :deployment_id, :env, :arguments, :error_message, :created_at, :updated_at)`
+	qry := `INSERT INTO services (id, name, image, ports, cpu, memory, storage, deployment_id, env, arguments, command, replicas, autoscale, 
+		        liveness_probe, readiness_probe, startup_probe, volumes, files, tls, tls_key, credentials, secret_env, error_message, created_at, updated_at) 
+		        VALUES (:id,:name, :image, :ports, :cpu, :memory, :storage, :deployment_id, :env, :arguments, :command, :replicas, :autoscale, 
+		        :liveness_probe, :readiness_probe, :startup_probe, :volumes, :files, :tls, :tls_key, :credentials, :secret_env, :error_message, :created_at, :updated_at)`
 	_, err := tx.NamedExecContext(ctx, qry, services)
 
 	return err
@@ -52,9 +70,7 @@ type DeploymentService struct {
 	types.Service `db:"service"`
 }
 
-func (m *ManagerDB) GetDeployments(ctx context.Context, option *types.GetDeploymentOption) ([]*types.Deployment, error) {
-	var ds []*DeploymentService
-	qry := `SELECT d.*, s.image as 'service.image', 
+const selectDeploymentServices = `SELECT d.*, s.image as 'service.image', 
 			s.name as 'service.name',
 			s.cpu as 'service.cpu', 
 			s.memory as 'service.memory',
@@ -62,17 +78,35 @@ func (m *ManagerDB) GetDeployments(ctx context.Context, option *types.GetDeploym
 			s.ports as 'service.ports', 
 			s.env as 'service.env', 
 			s.arguments as 'service.arguments', 
+			s.command as 'service.command', 
+			s.replicas as 'service.replicas', 
+			s.autoscale as 'service.autoscale', 
+			s.liveness_probe as 'service.liveness_probe', 
+			s.readiness_probe as 'service.readiness_probe', 
+			s.startup_probe as 'service.startup_probe', 
+			s.volumes as 'service.volumes', 
+			s.files as 'service.files', 
+			s.tls as 'service.tls', 
+			s.tls_key as 'service.tls_key', 
+			s.credentials as 'service.credentials', 
+			s.secret_env as 'service.secret_env', 
 			s.error_message  as 'service.error_message',
 			p.host_uri  as 'provider_expose_ip'
 		FROM deployments d LEFT JOIN services s ON d.id = s.deployment_id LEFT JOIN providers p ON d.provider_id = p.id`
 
+func (m *ManagerDB) GetDeployments(ctx context.Context, option *types.GetDeploymentOption) ([]*types.Deployment, error) {
+	qry := selectDeploymentServices
+
 	var condition []string
+	var args []interface{}
 	if option.DeploymentID != "" {
-		condition = append(condition, fmt.Sprintf(`d.id = '%s'`, option.DeploymentID))
+		condition = append(condition, `d.id = ?`)
+		args = append(args, option.DeploymentID)
 	}
 
 	if option.Owner != "" {
-		condition = append(condition, fmt.Sprintf(`d.owner = '%s'`, option.Owner))
+		condition = append(condition, `d.owner = ?`)
+		args = append(args, option.Owner)
 	}
 
 	if len(option.State) > 0 {
@@ -100,7 +134,27 @@ func (m *ManagerDB) GetDeployments(ctx context.Context, option *types.GetDeploym
 	limit := option.Size
 	qry += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 
-	err := m.db.SelectContext(ctx, &ds, qry)
+	return m.selectDeployments(ctx, qry, args...)
+}
+
+// GetAllDeployments returns every deployment in the given states, without paging
+func (m *ManagerDB) GetAllDeployments(ctx context.Context, states []types.DeploymentState) ([]*types.Deployment, error) {
+	qry := selectDeploymentServices
+
+	if len(states) > 0 {
+		var ss []string
+		for _, s := range states {
+			ss = append(ss, strconv.Itoa(int(s)))
+		}
+		qry += fmt.Sprintf(` WHERE d.state in (%s)`, strings.Join(ss, ","))
+	}
+
+	return m.selectDeployments(ctx, qry)
+}
+
+func (m *ManagerDB) selectDeployments(ctx context.Context, qry string, args ...interface{}) ([]*types.Deployment, error) {
+	var ds []*DeploymentService
+	err := m.db.SelectContext(ctx, &ds, qry, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,6 +180,40 @@ func (m *ManagerDB) UpdateDeploymentState(ctx context.Context, id types.Deployme
 	return err
 }
 
+// CloseDeployment marks the deployment closed, keeping the signature of the owner who requested it
+func (m *ManagerDB) CloseDeployment(ctx context.Context, id types.DeploymentID, signature *types.Signature) error {
+	qry := `Update deployments set state = ?, signature = ?, updated_at = ? where id = ?`
+	_, err := m.db.ExecContext(ctx, qry, types.DeploymentStateClose, signature, time.Now(), id)
+	return err
+}
+
+// UpdateServiceReplicas sets the replicas of a service along with the new cost of the deployment
+func (m *ManagerDB) UpdateServiceReplicas(ctx context.Context, id types.DeploymentID, serviceName string, replicas int, cost float64) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `Update services set replicas = ?, updated_at = ? where deployment_id = ? and name = ?`, replicas, time.Now(), id, serviceName)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `Update deployments set cost = ?, updated_at = ? where id = ?`, cost, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *ManagerDB) UpdateDeploymentExpiration(ctx context.Context, id types.DeploymentID, expiration time.Time) error {
+	qry := `Update deployments set expiration = ?, updated_at = ? where id = ?`
+	_, err := m.db.ExecContext(ctx, qry, expiration, time.Now(), id)
+	return err
+}
+
 func (m *ManagerDB) AddProperties(ctx context.Context, properties *types.Properties) error {
 	qry := `INSERT INTO properties (id, provider_id, app_id, app_type, created_at, updated_at) 
 		        VALUES (:id, :provider_id, :app_id, :app_type, :created_at, :updated_at) ON DUPLICATE KEY UPDATE 
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+)
+
+// DebitDeployment withdraws the amount from the deployment balance and records it in the ledger
+func (m *ManagerDB) DebitDeployment(ctx context.Context, entry *types.LedgerEntry, cost float64) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE deployments SET balance = balance - ?, cost = ?, updated_at = ? WHERE id = ?`, entry.Amount, cost, entry.CreatedAt, entry.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.GetContext(ctx, &entry.Balance, `SELECT balance FROM deployments WHERE id = ?`, entry.DeploymentID)
+	if err != nil {
+		return err
+	}
+
+	qry := `INSERT INTO deployment_ledger (deployment_id, owner, amount, balance, start_time, end_time, created_at) 
+		        VALUES (:deployment_id, :owner, :amount, :balance, :start_time, :end_time, :created_at)`
+	_, err = tx.NamedExecContext(ctx, qry, entry)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetLastBilledTimes returns the end of the last billed period of every deployment having ledger entries
+func (m *ManagerDB) GetLastBilledTimes(ctx context.Context) (map[types.DeploymentID]time.Time, error) {
+	var rows []struct {
+		DeploymentID types.DeploymentID `db:"deployment_id"`
+		EndTime      time.Time          `db:"end_time"`
+	}
+
+	err := m.db.SelectContext(ctx, &rows, `SELECT deployment_id, MAX(end_time) AS end_time FROM deployment_ledger GROUP BY deployment_id`)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[types.DeploymentID]time.Time, len(rows))
+	for _, row := range rows {
+		out[row.DeploymentID] = row.EndTime
+	}
+
+	return out, nil
+}
+
+func (m *ManagerDB) GetLedger(ctx context.Context, option *types.GetLedgerOption) ([]*types.LedgerEntry, error) {
+	qry := `SELECT * FROM deployment_ledger`
+
+	var condition []string
+	var args []interface{}
+	if option.Owner != "" {
+		condition = append(condition, `owner = ?`)
+		args = append(args, option.Owner)
+	}
+
+	if option.DeploymentID != "" {
+		condition = append(condition, `deployment_id = ?`)
+		args = append(args, option.DeploymentID)
+	}
+
+	if len(condition) > 0 {
+		qry += ` WHERE `
+		qry += strings.Join(condition, ` AND `)
+	}
+
+	if option.Page <= 0 {
+		option.Page = 1
+	}
+
+	if option.Size <= 0 {
+		option.Size = 10
+	}
+
+	offset := (option.Page - 1) * option.Size
+	qry += fmt.Sprintf(" ORDER BY id DESC LIMIT %d OFFSET %d", option.Size, offset)
+
+	var out []*types.LedgerEntry
+	err := m.db.SelectContext(ctx, &out, qry, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
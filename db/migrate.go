@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// columnMigration brings a column of a table created by an earlier version to its current
+// definition: the column is added when missing, and modified when its type differs
+type columnMigration struct {
+	table      string
+	column     string
+	dataType   string
+	definition string
+}
+
+var columnMigrations = []columnMigration{
+	{"services", "arguments", "text", "TEXT DEFAULT NULL"},
+	{"services", "command", "text", "TEXT DEFAULT NULL"},
+	{"services", "replicas", "int", "INT DEFAULT 1"},
+	{"services", "autoscale", "text", "TEXT DEFAULT NULL"},
+	{"services", "liveness_probe", "text", "TEXT DEFAULT NULL"},
+	{"services", "readiness_probe", "text", "TEXT DEFAULT NULL"},
+	{"services", "startup_probe", "text", "TEXT DEFAULT NULL"},
+	{"services", "volumes", "text", "TEXT DEFAULT NULL"},
+	{"services", "files", "mediumtext", "MEDIUMTEXT DEFAULT NULL"},
+	{"services", "tls", "text", "TEXT DEFAULT NULL"},
+	{"services", "tls_key", "blob", "BLOB DEFAULT NULL"},
+	{"services", "credentials", "blob", "BLOB DEFAULT NULL"},
+	{"services", "secret_env", "blob", "BLOB DEFAULT NULL"},
+	{"deployments", "signature", "text", "TEXT DEFAULT NULL"},
+	{"deployments", "balance", "decimal", "DECIMAL(20,6) DEFAULT 0"},
+	{"deployments", "cost", "decimal", "DECIMAL(20,6) DEFAULT 0"},
+	{"accounts", "balance", "decimal", "DECIMAL(20,6) DEFAULT 0"},
+	{"deployment_ledger", "amount", "decimal", "DECIMAL(20,6) DEFAULT 0"},
+	{"deployment_ledger", "balance", "decimal", "DECIMAL(20,6) DEFAULT 0"},
+}
+
+// migrateColumns applies the column migrations, it is idempotent
+func migrateColumns(ctx context.Context, mainDB *sqlx.DB) error {
+	for _, m := range columnMigrations {
+		var dataType string
+		qry := `SELECT DATA_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+		err := mainDB.GetContext(ctx, &dataType, qry, m.table, m.column)
+
+		var alter string
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			alter = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, m.table, m.column, m.definition)
+		case err != nil:
+			return err
+		case dataType != m.dataType:
+			alter = fmt.Sprintf(`ALTER TABLE %s MODIFY COLUMN %s %s`, m.table, m.column, m.definition)
+		default:
+			continue
+		}
+
+		log.Infof("db: migrating column %s.%s", m.table, m.column)
+		if _, err := mainDB.ExecContext(ctx, alter); err != nil {
+			return errors.Errorf("failed to migrate column %s.%s: %v", m.table, m.column, err)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	_ "github.com/go-sql-driver/mysql"
@@ -33,12 +34,15 @@ func (m *ManagerDB) AddNewProvider(ctx context.Context, provider *types.Provider
 func (m *ManagerDB) GetAllProviders(ctx context.Context, option *types.GetProviderOption) ([]*types.Provider, error) {
 	qry := `SELECT * from providers`
 	var condition []string
+	var args []interface{}
 	if option.ID != "" {
-		condition = append(condition, fmt.Sprintf(`id = '%s'`, option.ID))
+		condition = append(condition, `id = ?`)
+		args = append(args, option.ID)
 	}
 
 	if option.Owner != "" {
-		condition = append(condition, fmt.Sprintf(`owner = '%s'`, option.Owner))
+		condition = append(condition, `owner = ?`)
+		args = append(args, option.Owner)
 	}
 
 	if len(option.State) > 0 {
@@ -67,9 +71,52 @@ func (m *ManagerDB) GetAllProviders(ctx context.Context, option *types.GetProvid
 	qry += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 
 	var out []*types.Provider
-	err := m.db.SelectContext(ctx, &out, qry)
+	err := m.db.SelectContext(ctx, &out, qry, args...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
+
+// UpdateProviderState persists a provider state transition and records it in the state history
+func (m *ManagerDB) UpdateProviderState(ctx context.Context, id types.ProviderID, state types.ProviderState, at time.Time) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `UPDATE providers SET state = ?, updated_at = ? WHERE id = ?`, state, at, id)
+	if err != nil {
+		return err
+	}
+
+	history := &types.ProviderStateHistory{ProviderID: id, State: state, CreatedAt: at}
+	_, err = tx.NamedExecContext(ctx, `INSERT INTO provider_state_history (provider_id, state, created_at) VALUES (:provider_id, :state, :created_at)`, history)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetProviderStates moves every provider which is not yet in the given state to it, recording the transitions
+func (m *ManagerDB) ResetProviderStates(ctx context.Context, state types.ProviderState, at time.Time) error {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO provider_state_history (provider_id, state, created_at) SELECT id, ?, ? FROM providers WHERE state != ?`, state, at, state)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE providers SET state = ?, updated_at = ? WHERE state != ?`, state, at, state)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+func addDeploymentRevision(ctx context.Context, tx *sqlx.Tx, revision *types.DeploymentRevision) error {
+	qry := `INSERT INTO deployment_revisions (deployment_id, revision, author, services, summary, created_at) 
+		        VALUES (:deployment_id, :revision, :author, :services, :summary, :created_at)`
+	_, err := tx.NamedExecContext(ctx, qry, revision)
+
+	return err
+}
+
+func (m *ManagerDB) GetDeploymentRevisions(ctx context.Context, id types.DeploymentID) ([]*types.DeploymentRevision, error) {
+	var out []*types.DeploymentRevision
+	err := m.db.SelectContext(ctx, &out, `SELECT * FROM deployment_revisions WHERE deployment_id = ? ORDER BY revision`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (m *ManagerDB) GetDeploymentRevision(ctx context.Context, id types.DeploymentID, revision int64) (*types.DeploymentRevision, error) {
+	var out types.DeploymentRevision
+	err := m.db.GetContext(ctx, &out, `SELECT * FROM deployment_revisions WHERE deployment_id = ? AND revision = ?`, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetLatestDeploymentRevision returns the last revision of the deployment, or nil if it has none
+func (m *ManagerDB) GetLatestDeploymentRevision(ctx context.Context, id types.DeploymentID) (*types.DeploymentRevision, error) {
+	var out types.DeploymentRevision
+	err := m.db.GetContext(ctx, &out, `SELECT * FROM deployment_revisions WHERE deployment_id = ? ORDER BY revision DESC LIMIT 1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
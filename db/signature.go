@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+var ErrNonceUsed = errors.New("the signature nonce was already used")
+
+// mysql error of a duplicate primary or unique key
+const errDupEntry = 1062
+
+// UseSignatureNonce records the nonce of a signed request on the deployment, ErrNonceUsed is
+// returned when a request with the same nonce was already accepted
+func (m *ManagerDB) UseSignatureNonce(ctx context.Context, id types.DeploymentID, owner string, sig *types.Signature, at time.Time) error {
+	qry := `INSERT INTO signature_nonces (nonce, owner, action, deployment_id, signed_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := m.db.ExecContext(ctx, qry, sig.Nonce, owner, sig.Action, id, sig.SignedAt, at)
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDupEntry {
+		return ErrNonceUsed
+	}
+	return err
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS accounts(
+    owner VARCHAR(128) NOT NULL,
+    balance DECIMAL(20,6) DEFAULT 0,
+    updated_at DATETIME     DEFAULT NULL,
+    PRIMARY KEY (owner)
+)ENGINE=InnoDB COMMENT='credit of the deployment owners';
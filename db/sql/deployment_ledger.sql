@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS deployment_ledger(
+    id INT UNSIGNED AUTO_INCREMENT,
+    deployment_id VARCHAR(128) NOT NULL,
+    owner VARCHAR(128) NOT NULL DEFAULT '',
+    amount DECIMAL(20,6) DEFAULT 0,
+    balance DECIMAL(20,6) DEFAULT 0,
+    start_time DATETIME     DEFAULT NULL,
+    end_time DATETIME     DEFAULT NULL,
+    created_at DATETIME     DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY idx_deployment_id (deployment_id),
+    KEY idx_owner (owner)
+)ENGINE=InnoDB COMMENT='deployment ledger';
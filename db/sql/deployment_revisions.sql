@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS deployment_revisions(
+    id INT UNSIGNED AUTO_INCREMENT,
+    deployment_id VARCHAR(128) NOT NULL,
+    revision INT DEFAULT 0,
+    author VARCHAR(128) NOT NULL DEFAULT '',
+    services MEDIUMTEXT,
+    summary TEXT,
+    created_at DATETIME     DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uk_deployment_revision (deployment_id, revision)
+)ENGINE=InnoDB COMMENT='deployment revisions';
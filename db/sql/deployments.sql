@@ -6,10 +6,11 @@ CREATE TABLE IF NOT EXISTS deployments(
     type INT DEFAULT 0,
     authority TINYINT(1) DEFAULT 0,
     version VARCHAR(128) DEFAULT '',
-    balance FLOAT        DEFAULT 0,
-    cost FLOAT        DEFAULT 0,
+    balance DECIMAL(20,6) DEFAULT 0,
+    cost DECIMAL(20,6) DEFAULT 0,
     provider_id VARCHAR(128) NOT NULL,
     expiration DATETIME     DEFAULT NULL,
+    signature TEXT         DEFAULT NULL,
     created_at DATETIME     DEFAULT NULL,
     updated_at DATETIME     DEFAULT NULL
     )ENGINE=InnoDB COMMENT='deployments';
\ No newline at end of file
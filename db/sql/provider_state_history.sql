@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS provider_state_history(
+    id INT UNSIGNED AUTO_INCREMENT,
+    provider_id VARCHAR(128) NOT NULL,
+    state INT DEFAULT 0,
+    created_at DATETIME     DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY idx_provider_id (provider_id)
+)ENGINE=InnoDB COMMENT='provider state history';
\ No newline at end of file
@@ -9,7 +9,19 @@ CREATE TABLE IF NOT EXISTS services(
     memory FLOAT        DEFAULT 0,
     storage FLOAT        DEFAULT 0,
     env VARCHAR(128) DEFAULT NULL,
-    arguments VARCHAR(128) DEFAULT NULL,
+    arguments TEXT DEFAULT NULL,
+    command TEXT DEFAULT NULL,
+    replicas INT DEFAULT 1,
+    autoscale TEXT DEFAULT NULL,
+    liveness_probe TEXT DEFAULT NULL,
+    readiness_probe TEXT DEFAULT NULL,
+    startup_probe TEXT DEFAULT NULL,
+    volumes TEXT DEFAULT NULL,
+    files MEDIUMTEXT DEFAULT NULL,
+    tls TEXT DEFAULT NULL,
+    tls_key BLOB DEFAULT NULL,
+    credentials BLOB DEFAULT NULL,
+    secret_env BLOB DEFAULT NULL,
     deployment_id VARCHAR(128) NOT NULL,
     error_message VARCHAR(128) DEFAULT NULL,
     created_at DATETIME     DEFAULT NULL,
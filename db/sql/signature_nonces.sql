@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS signature_nonces(
+    nonce VARCHAR(64) NOT NULL,
+    owner VARCHAR(128) NOT NULL DEFAULT '',
+    action VARCHAR(16) NOT NULL DEFAULT '',
+    deployment_id VARCHAR(128) NOT NULL DEFAULT '',
+    signed_at DATETIME     DEFAULT NULL,
+    created_at DATETIME     DEFAULT NULL,
+    PRIMARY KEY (nonce)
+)ENGINE=InnoDB COMMENT='nonces of the signed requests, they are accepted once';
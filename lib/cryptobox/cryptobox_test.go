@@ -0,0 +1,37 @@
+package cryptobox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	key, err := GenerateKey()
+	require.NoError(t, err)
+
+	box, err := NewBox(key)
+	require.NoError(t, err)
+
+	sealed, err := box.Seal([]byte("secret"))
+	require.NoError(t, err)
+	require.NotContains(t, string(sealed), "secret")
+
+	plaintext, err := box.Open(sealed)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(plaintext))
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = box.Open(sealed)
+	require.ErrorIs(t, err, ErrInvalidSealed)
+
+	otherKey, err := GenerateKey()
+	require.NoError(t, err)
+	other, err := NewBox(otherKey)
+	require.NoError(t, err)
+
+	sealed, err = box.Seal([]byte("secret"))
+	require.NoError(t, err)
+	_, err = other.Open(sealed)
+	require.ErrorIs(t, err, ErrInvalidSealed)
+}
@@ -0,0 +1,174 @@
+// Package execstream multiplexes the stdin of a remote command and the resizes
+// of its terminal over a single byte stream.
+//
+// Every frame is a one byte type, followed by the big endian uint32 length of
+// the payload and the payload itself. The payload of a resize frame is the
+// width and the height of the terminal as big endian uint16.
+package execstream
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	frameStdin byte = iota + 1
+	frameResize
+)
+
+const (
+	headerSize = 5
+	// MaxFrameSize is the largest payload accepted by the decoder
+	MaxFrameSize = 1 << 20
+
+	sizesBufferSize = 8
+	// number of stdin frames buffered for a command which does not read its stdin yet
+	stdinBufferSize = 64
+)
+
+// TerminalSize is the size of the terminal of a remote command
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
+// Writer encodes stdin data and terminal resizes into frames
+type Writer struct {
+	lk sync.Mutex
+	w  io.WriteCloser
+}
+
+func NewWriter(w io.WriteCloser) *Writer {
+	return &Writer{w: w}
+}
+
+// Write sends p as stdin of the remote command
+func (w *Writer) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := len(p)
+		if n > MaxFrameSize {
+			n = MaxFrameSize
+		}
+
+		if err := w.writeFrame(frameStdin, p[:n]); err != nil {
+			return written, err
+		}
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Resize sends the new size of the local terminal
+func (w *Writer) Resize(size TerminalSize) error {
+	payload := make([]byte, 4)
+	binary.BigEndian.PutUint16(payload[0:], size.Width)
+	binary.BigEndian.PutUint16(payload[2:], size.Height)
+	return w.writeFrame(frameResize, payload)
+}
+
+// Close ends the stream, the remote command then reads EOF from its stdin
+func (w *Writer) Close() error {
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	return w.w.Close()
+}
+
+func (w *Writer) writeFrame(t byte, payload []byte) error {
+	frame := make([]byte, headerSize+len(payload))
+	frame[0] = t
+	binary.BigEndian.PutUint32(frame[1:headerSize], uint32(len(payload)))
+	copy(frame[headerSize:], payload)
+
+	w.lk.Lock()
+	defer w.lk.Unlock()
+	_, err := w.w.Write(frame)
+	return err
+}
+
+// Demux decodes the frames read from r. The stdin data is returned as a reader and
+// the terminal sizes are sent on the channel, which is closed when r is exhausted.
+//
+// The stdin is written apart from the decoding, so the resizes keep flowing when the
+// stdin is not read: the stdin data is discarded once the reader is closed, and the
+// frames beyond the buffer of a command which does not read its stdin are dropped.
+func Demux(r io.Reader) (io.ReadCloser, <-chan TerminalSize) {
+	pr, pw := io.Pipe()
+	sizes := make(chan TerminalSize, sizesBufferSize)
+	frames := make(chan []byte, stdinBufferSize)
+
+	var demuxErr error
+	go func() {
+		defer close(sizes)
+		defer close(frames)
+		demuxErr = demux(r, frames, sizes)
+	}()
+
+	go func() {
+		var err error
+		for payload := range frames {
+			if err == nil {
+				_, err = pw.Write(payload)
+			}
+		}
+		pw.CloseWithError(demuxErr)
+	}()
+
+	return pr, sizes
+}
+
+func demux(r io.Reader, stdin chan<- []byte, sizes chan TerminalSize) error {
+	header := make([]byte, headerSize)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return xerrors.Errorf("read frame header: %w", err)
+		}
+
+		length := binary.BigEndian.Uint32(header[1:])
+		if length > MaxFrameSize {
+			return xerrors.Errorf("frame of %d bytes is too large", length)
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return xerrors.Errorf("read frame payload: %w", err)
+		}
+
+		switch header[0] {
+		case frameStdin:
+			select {
+			case stdin <- payload:
+			default:
+			}
+		case frameResize:
+			if len(payload) != 4 {
+				return xerrors.Errorf("invalid resize frame of %d bytes", len(payload))
+			}
+
+			size := TerminalSize{
+				Width:  binary.BigEndian.Uint16(payload[0:]),
+				Height: binary.BigEndian.Uint16(payload[2:]),
+			}
+
+			// only the latest size matters, drop the oldest one if nobody reads them
+			select {
+			case sizes <- size:
+			default:
+				select {
+				case <-sizes:
+				default:
+				}
+				sizes <- size
+			}
+		default:
+			return xerrors.Errorf("unknown frame type %d", header[0])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package execstream
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	pr, pw := io.Pipe()
+	w := NewWriter(pw)
+
+	go func() {
+		_, _ = w.Write([]byte("echo "))
+		_ = w.Resize(TerminalSize{Width: 80, Height: 24})
+		_, _ = w.Write([]byte("hello\n"))
+		_ = w.Close()
+	}()
+
+	stdin, sizes := Demux(pr)
+
+	data, err := io.ReadAll(stdin)
+	require.NoError(t, err)
+	require.Equal(t, "echo hello\n", string(data))
+
+	size, ok := <-sizes
+	require.True(t, ok)
+	require.Equal(t, TerminalSize{Width: 80, Height: 24}, size)
+
+	_, ok = <-sizes
+	require.False(t, ok)
+}
+
+func TestResizeWithoutStdinReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	w := NewWriter(pw)
+
+	stdin, sizes := Demux(pr)
+	// the stdin of the command is off, or it never reads it
+	require.NoError(t, stdin.Close())
+
+	go func() {
+		for i := 0; i < stdinBufferSize*2; i++ {
+			_, _ = w.Write([]byte("input"))
+		}
+		_ = w.Resize(TerminalSize{Width: 80, Height: 24})
+		_ = w.Close()
+	}()
+
+	size, ok := <-sizes
+	require.True(t, ok)
+	require.Equal(t, TerminalSize{Width: 80, Height: 24}, size)
+}
+
+func TestResizeWithUnreadStdin(t *testing.T) {
+	pr, pw := io.Pipe()
+	w := NewWriter(pw)
+
+	_, sizes := Demux(pr)
+
+	go func() {
+		for i := 0; i < stdinBufferSize*2; i++ {
+			_, _ = w.Write([]byte("input"))
+		}
+		_ = w.Resize(TerminalSize{Width: 100, Height: 40})
+	}()
+
+	size, ok := <-sizes
+	require.True(t, ok)
+	require.Equal(t, TerminalSize{Width: 100, Height: 40}, size)
+}
@@ -0,0 +1,175 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	nodetypes "github.com/Filecoin-Titan/titan-container/node/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/pkg/errors"
+)
+
+const (
+	KTSecp256k1 nodetypes.KeyType = "secp256k1"
+	KTEd25519   nodetypes.KeyType = "ed25519"
+)
+
+// DefaultAddressPrefix is the bech32 prefix of the owner addresses
+const DefaultAddressPrefix = "titan"
+
+// MaxAge is how long a signature stays valid after it was made
+var MaxAge = 10 * time.Minute
+
+const nonceSize = 16
+
+var (
+	ErrSignatureRequired = errors.New("the request must be signed by the deployment owner")
+	ErrInvalidSignature  = errors.New("invalid deployment signature")
+)
+
+// GenerateKey returns a new private key of the given type
+func GenerateKey(keyType nodetypes.KeyType) (nodetypes.KeyInfo, error) {
+	switch keyType {
+	case KTSecp256k1:
+		return nodetypes.KeyInfo{Type: keyType, PrivateKey: secp256k1.GenPrivKey().Bytes()}, nil
+	case KTEd25519:
+		return nodetypes.KeyInfo{Type: keyType, PrivateKey: ed25519.GenPrivKey().Bytes()}, nil
+	default:
+		return nodetypes.KeyInfo{}, errors.Errorf("unsupported key type %s", keyType)
+	}
+}
+
+// Address returns the bech32 address of the key with the given prefix
+func Address(key nodetypes.KeyInfo, prefix string) (string, error) {
+	privKey, err := privateKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return bech32.ConvertAndEncode(prefix, privKey.PubKey().Address())
+}
+
+// SignDeployment signs the deployment for the action, the signature is attached to the deployment
+func SignDeployment(key nodetypes.KeyInfo, deployment *types.Deployment, action types.SignAction) error {
+	return sign(key, deployment, &types.Signature{Action: action})
+}
+
+// SignRollback signs the rollback of the deployment to the revision, the signature is attached to the deployment
+func SignRollback(key nodetypes.KeyInfo, deployment *types.Deployment, revision int64) error {
+	return sign(key, deployment, &types.Signature{Action: types.SignActionRollback, Revision: revision})
+}
+
+func sign(key nodetypes.KeyInfo, deployment *types.Deployment, sig *types.Signature) error {
+	privKey, err := privateKey(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	sig.KeyType = string(key.Type)
+	sig.PubKey = privKey.PubKey().Bytes()
+	sig.SignedAt = time.Now()
+	sig.Nonce = hex.EncodeToString(nonce)
+
+	msg, err := types.DeploymentSignBytes(deployment, sig)
+	if err != nil {
+		return err
+	}
+
+	sig.Signature, err = privKey.Sign(msg)
+	if err != nil {
+		return err
+	}
+
+	deployment.Signature = sig
+	return nil
+}
+
+// VerifyDeployment checks the deployment signature was made recently for the action, by the key of the deployment owner
+func VerifyDeployment(deployment *types.Deployment, action types.SignAction) error {
+	sig := deployment.Signature
+	if sig == nil {
+		return ErrSignatureRequired
+	}
+
+	if sig.Action != action {
+		return errors.Wrapf(ErrInvalidSignature, "signed for %s instead of %s", sig.Action, action)
+	}
+
+	if age := time.Since(sig.SignedAt); age > MaxAge || age < -MaxAge {
+		return errors.Wrap(ErrInvalidSignature, "signature expired")
+	}
+
+	if sig.Nonce == "" {
+		return errors.Wrap(ErrInvalidSignature, "missing nonce")
+	}
+
+	pubKey, err := publicKey(nodetypes.KeyType(sig.KeyType), sig.PubKey)
+	if err != nil {
+		return err
+	}
+
+	_, owner, err := bech32.DecodeAndConvert(deployment.Owner)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "decoding owner address: %v", err)
+	}
+
+	if !bytes.Equal(owner, pubKey.Address()) {
+		return errors.Wrap(ErrInvalidSignature, "the key does not belong to the owner")
+	}
+
+	msg, err := types.DeploymentSignBytes(deployment, sig)
+	if err != nil {
+		return err
+	}
+
+	if !pubKey.VerifySignature(msg, sig.Signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+func privateKey(key nodetypes.KeyInfo) (cryptotypes.PrivKey, error) {
+	switch key.Type {
+	case KTSecp256k1:
+		if len(key.PrivateKey) != secp256k1.PrivKeySize {
+			return nil, errors.New("invalid secp256k1 private key size")
+		}
+		return &secp256k1.PrivKey{Key: key.PrivateKey}, nil
+	case KTEd25519:
+		if len(key.PrivateKey) != ed25519.PrivKeySize {
+			return nil, errors.New("invalid ed25519 private key size")
+		}
+		return &ed25519.PrivKey{Key: key.PrivateKey}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %s", key.Type)
+	}
+}
+
+func publicKey(keyType nodetypes.KeyType, key []byte) (cryptotypes.PubKey, error) {
+	switch keyType {
+	case KTSecp256k1:
+		if len(key) != secp256k1.PubKeySize {
+			return nil, errors.Wrap(ErrInvalidSignature, "invalid secp256k1 public key size")
+		}
+		return &secp256k1.PubKey{Key: key}, nil
+	case KTEd25519:
+		if len(key) != ed25519.PubKeySize {
+			return nil, errors.Wrap(ErrInvalidSignature, "invalid ed25519 public key size")
+		}
+		return &ed25519.PubKey{Key: key}, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidSignature, "unsupported key type %s", keyType)
+	}
+}
@@ -0,0 +1,76 @@
+package signature
+
+import (
+	"testing"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	nodetypes "github.com/Filecoin-Titan/titan-container/node/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignDeployment(t *testing.T) {
+	for _, keyType := range []nodetypes.KeyType{KTSecp256k1, KTEd25519} {
+		key, err := GenerateKey(keyType)
+		require.NoError(t, err)
+
+		owner, err := Address(key, "titan")
+		require.NoError(t, err)
+
+		deployment := &types.Deployment{
+			Owner:    owner,
+			Name:     "test",
+			Services: []*types.Service{{Image: "nginx", ComputeResources: types.ComputeResources{CPU: 1}}},
+		}
+
+		require.ErrorIs(t, VerifyDeployment(deployment, types.SignActionCreate), ErrSignatureRequired)
+
+		require.NoError(t, SignDeployment(key, deployment, types.SignActionCreate))
+		require.NoError(t, VerifyDeployment(deployment, types.SignActionCreate))
+		require.Error(t, VerifyDeployment(deployment, types.SignActionUpdate))
+
+		// status reported by the provider is not signed
+		deployment.Services[0].Status.ReadyReplicas = 1
+		require.NoError(t, VerifyDeployment(deployment, types.SignActionCreate))
+
+		// the id is assigned by the manager after signing
+		deployment.ID = "id"
+		require.NoError(t, VerifyDeployment(deployment, types.SignActionCreate))
+
+		nonce := deployment.Signature.Nonce
+		deployment.Signature.Nonce = ""
+		require.ErrorIs(t, VerifyDeployment(deployment, types.SignActionCreate), ErrInvalidSignature)
+		deployment.Signature.Nonce = "other"
+		require.ErrorIs(t, VerifyDeployment(deployment, types.SignActionCreate), ErrInvalidSignature)
+		deployment.Signature.Nonce = nonce
+
+		deployment.Services[0].Image = "evil"
+		require.Error(t, VerifyDeployment(deployment, types.SignActionCreate))
+		deployment.Services[0].Image = "nginx"
+
+		other, err := GenerateKey(KTSecp256k1)
+		require.NoError(t, err)
+		deployment.Owner, err = Address(other, "titan")
+		require.NoError(t, err)
+		require.Error(t, VerifyDeployment(deployment, types.SignActionCreate))
+	}
+}
+
+func TestSignRollback(t *testing.T) {
+	key, err := GenerateKey(KTSecp256k1)
+	require.NoError(t, err)
+
+	owner, err := Address(key, "titan")
+	require.NoError(t, err)
+
+	deployment := &types.Deployment{ID: "id", Owner: owner}
+	require.NoError(t, SignRollback(key, deployment, 2))
+	require.NoError(t, VerifyDeployment(deployment, types.SignActionRollback))
+	require.Error(t, VerifyDeployment(deployment, types.SignActionClose))
+
+	deployment.Signature.Revision = 1
+	require.ErrorIs(t, VerifyDeployment(deployment, types.SignActionRollback), ErrInvalidSignature)
+	deployment.Signature.Revision = 2
+
+	deployment.ID = "other"
+	require.ErrorIs(t, VerifyDeployment(deployment, types.SignActionRollback), ErrInvalidSignature)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/lib/cryptobox"
 	"github.com/Filecoin-Titan/titan-container/node/impl/manager"
 	"github.com/Filecoin-Titan/titan-container/node/modules"
 	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
@@ -47,7 +48,11 @@ func ConfigManager(c interface{}) Option {
 		ConfigCommon(&cfg.Common),
 		Override(new(*sqlx.DB), modules.NewManagerDB(cfg.DatabaseAddress)),
 		Override(new(*db.ManagerDB), db.NewManagerDB),
+		Override(new(*cryptobox.Box), modules.CredentialsBox),
 		Override(new(*manager.ProviderManager), manager.NewProviderScheduler),
+		Override(new(*manager.Reconciler), manager.NewReconciler),
+		Override(new(*manager.LeaseManager), manager.NewLeaseManager),
+		Override(new(*manager.Billing), manager.NewBilling),
 		Override(new(dtypes.SetManagerConfigFunc), modules.NewSetManagerConfigFunc),
 		Override(new(dtypes.GetManagerConfigFunc), modules.NewGetManagerConfigFunc),
 	)
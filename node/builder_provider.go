@@ -6,6 +6,8 @@ import (
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/node/config"
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider"
+	"github.com/Filecoin-Titan/titan-container/node/modules"
+	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
 	"github.com/Filecoin-Titan/titan-container/node/repo"
 	"go.uber.org/fx"
 
@@ -41,6 +43,7 @@ func ConfigProvider(c interface{}) Option {
 	return Options(
 		ConfigCommon(&cfg.Common),
 		Override(new(*config.ProviderCfg), cfg),
+		Override(new(dtypes.GetProviderConfigFunc), modules.NewGetProviderConfigFunc),
 		Override(new(provider.Manager), provider.NewManager),
 	)
 }
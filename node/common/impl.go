@@ -31,15 +31,11 @@ type CommonAPI struct {
 
 // MethodGroup: Auth
 
-type jwtPayload struct {
-	Allow []auth.Permission
-}
-
 // AuthVerify verifies a JWT token and returns the permissions associated with it
 func (a *CommonAPI) AuthVerify(ctx context.Context, token string) ([]auth.Permission, error) {
-	var payload jwtPayload
-	if _, err := jwt.Verify([]byte(token), (*jwt.HMACSHA)(a.APISecret), &payload); err != nil {
-		return nil, xerrors.Errorf("JWT Verification failed: %w", err)
+	payload, err := a.AuthVerifyPayload(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 
 	return payload.Allow, nil
@@ -47,8 +43,24 @@ func (a *CommonAPI) AuthVerify(ctx context.Context, token string) ([]auth.Permis
 
 // AuthNew generates a new JWT token with the provided permissions
 func (a *CommonAPI) AuthNew(ctx context.Context, perms []auth.Permission) ([]byte, error) {
-	p := jwtPayload{
+	return a.AuthNewOwner(ctx, perms, "")
+}
+
+// AuthVerifyPayload verifies a JWT token and returns the permissions and the owner associated with it
+func (a *CommonAPI) AuthVerifyPayload(ctx context.Context, token string) (*types.JWTPayload, error) {
+	var payload types.JWTPayload
+	if _, err := jwt.Verify([]byte(token), (*jwt.HMACSHA)(a.APISecret), &payload); err != nil {
+		return nil, xerrors.Errorf("JWT Verification failed: %w", err)
+	}
+
+	return &payload, nil
+}
+
+// AuthNewOwner generates a new JWT token with the provided permissions, bound to the owner
+func (a *CommonAPI) AuthNewOwner(ctx context.Context, perms []auth.Permission, owner string) ([]byte, error) {
+	p := types.JWTPayload{
 		Allow: perms, // TODO: consider checking validity
+		Owner: owner,
 	}
 
 	return jwt.Sign(&p, (*jwt.HMACSHA)(a.APISecret))
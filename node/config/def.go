@@ -36,6 +36,9 @@ func DefaultProviderCfg() *ProviderCfg {
 		Owner:   "",
 		HostURI: "",
 		Timeout: "30s",
+		Kube: KubeCfg{
+			ServiceType: "NodePort",
+		},
 	}
 }
 
@@ -48,7 +51,16 @@ func DefaultManagerCfg() *ManagerCfg {
 				RemoteListenAddress: "",
 			},
 		},
-		DatabaseAddress: "mysql_user:mysql_password@tcp(127.0.0.1:3306)/titan_container?parseTime=true",
+		DatabaseAddress:        "mysql_user:mysql_password@tcp(127.0.0.1:3306)/titan_container?parseTime=true",
+		ProviderSelectStrategy: "least-loaded",
+		ReconcileInterval:      Duration(5 * time.Minute),
+		ReconcileMode:          "report",
+		DefaultLeaseDuration:   Duration(30 * 24 * time.Hour),
+		LeaseGracePeriod:       Duration(24 * time.Hour),
+		Pricing: PricingCfg{
+			BillingInterval: Duration(time.Hour),
+			ExhaustedState:  "inactive",
+		},
 	}
 }
 
@@ -37,6 +37,88 @@ var Doc = map[string][]DocField{
 			Comment: ``,
 		},
 	},
+	"KubeCfg": []DocField{
+		{
+			Name: "ServiceType",
+			Type: "string",
+
+			Comment: `type of the services exposing the global ports, NodePort or LoadBalancer`,
+		},
+		{
+			Name: "IngressStaticHosts",
+			Type: "bool",
+
+			Comment: `generate a host under IngressDomain for the http ports without host`,
+		},
+		{
+			Name: "IngressDomain",
+			Type: "string",
+
+			Comment: `domain the hosts of the deployments are generated under`,
+		},
+		{
+			Name: "IngressExposeLBHosts",
+			Type: "bool",
+
+			Comment: `report the load balancer hosts of the ingresses with the hosts of the deployments`,
+		},
+		{
+			Name: "IngressClusterIssuer",
+			Type: "string",
+
+			Comment: `cert-manager ClusterIssuer of the certificates of the ingress hosts, when the
+deployments do not supply theirs. Https is only available with supplied certificates when empty.`,
+		},
+		{
+			Name: "ClusterPublicHostname",
+			Type: "string",
+
+			Comment: `hostname the global ports are reachable at, the PublicIP when empty`,
+		},
+		{
+			Name: "NetworkPoliciesEnabled",
+			Type: "bool",
+
+			Comment: `isolate the deployments from each other with network policies`,
+		},
+		{
+			Name: "CPUCommitLevel",
+			Type: "float64",
+
+			Comment: `overcommit factors, the pods request their limits divided by the factor.
+The full limits are requested when 1 or less`,
+		},
+		{
+			Name: "GPUCommitLevel",
+			Type: "float64",
+
+			Comment: ``,
+		},
+		{
+			Name: "MemoryCommitLevel",
+			Type: "float64",
+
+			Comment: ``,
+		},
+		{
+			Name: "StorageCommitLevel",
+			Type: "float64",
+
+			Comment: ``,
+		},
+		{
+			Name: "RuntimeClass",
+			Type: "string",
+
+			Comment: `runtime class of the pods, the default runtime of the cluster when empty`,
+		},
+		{
+			Name: "ImagePullSecretsName",
+			Type: "string",
+
+			Comment: `image pull secret of the namespace of the deployments used by the pods`,
+		},
+	},
 	"ManagerCfg": []DocField{
 		{
 			Name: "DatabaseAddress",
@@ -44,6 +126,74 @@ var Doc = map[string][]DocField{
 
 			Comment: `database address`,
 		},
+		{
+			Name: "ProviderSelectStrategy",
+			Type: "string",
+
+			Comment: `strategy used to pick a provider for deployments that do not specify one, one of: least-loaded, bin-packing`,
+		},
+		{
+			Name: "ReconcileInterval",
+			Type: "Duration",
+
+			Comment: `interval between two reconciliations of the deployments with the providers, 0 disables it`,
+		},
+		{
+			Name: "ReconcileMode",
+			Type: "string",
+
+			Comment: `what the reconciler does on drift, one of: report, repair`,
+		},
+		{
+			Name: "DefaultLeaseDuration",
+			Type: "Duration",
+
+			Comment: `lease given to deployments created without an expiration, 0 makes the expiration mandatory`,
+		},
+		{
+			Name: "LeaseGracePeriod",
+			Type: "Duration",
+
+			Comment: `how long an expired deployment keeps running before it is closed`,
+		},
+		{
+			Name: "Pricing",
+			Type: "PricingCfg",
+
+			Comment: `billing of the deployments`,
+		},
+	},
+	"PricingCfg": []DocField{
+		{
+			Name: "Enable",
+			Type: "bool",
+
+			Comment: `enables the billing of deployments, deployments without balance are then rejected`,
+		},
+		{
+			Name: "BillingInterval",
+			Type: "Duration",
+
+			Comment: `interval between two debits of the deployment balances`,
+		},
+		{
+			Name: "ExhaustedState",
+			Type: "string",
+
+			Comment: `state of a deployment whose balance runs out, one of: inactive, close`,
+		},
+		{
+			Name: "Default",
+			Type: "ResourcePrice",
+
+			Comment: `hourly prices of the resources`,
+		},
+		{
+			Name: "Providers",
+			Type: "map[string]ResourcePrice",
+
+			Comment: `hourly prices overriding the default ones, keyed by provider id`,
+		},
 	},
 	"ProviderCfg": []DocField{
 		{
@@ -52,5 +202,55 @@ var Doc = map[string][]DocField{
 
 			Comment: `used when 'ListenAddress' is unspecified. must be a valid duration recognized by golang's time.ParseDuration function`,
 		},
+		{
+			Name: "Owner",
+			Type: "string",
+
+			Comment: ``,
+		},
+		{
+			Name: "HostURI",
+			Type: "string",
+
+			Comment: ``,
+		},
+		{
+			Name: "PublicIP",
+			Type: "string",
+
+			Comment: ``,
+		},
+		{
+			Name: "KubeConfigPath",
+			Type: "string",
+
+			Comment: ``,
+		},
+		{
+			Name: "Kube",
+			Type: "KubeCfg",
+
+			Comment: `kubernetes objects of the deployments, reloaded on every deployment`,
+		},
+	},
+	"ResourcePrice": []DocField{
+		{
+			Name: "CPU",
+			Type: "float64",
+
+			Comment: `price of one cpu core per hour`,
+		},
+		{
+			Name: "Memory",
+			Type: "float64",
+
+			Comment: `price of one GB of memory per hour`,
+		},
+		{
+			Name: "Storage",
+			Type: "float64",
+
+			Comment: `price of one GB of storage per hour`,
+		},
 	},
 }
@@ -21,6 +21,42 @@ type ManagerCfg struct {
 	Common
 	// database address
 	DatabaseAddress string
+	// strategy used to pick a provider for deployments that do not specify one, one of: least-loaded, bin-packing
+	ProviderSelectStrategy string
+	// interval between two reconciliations of the deployments with the providers, 0 disables it
+	ReconcileInterval Duration
+	// what the reconciler does on drift, one of: report, repair
+	ReconcileMode string
+	// lease given to deployments created without an expiration, 0 makes the expiration mandatory
+	DefaultLeaseDuration Duration
+	// how long an expired deployment keeps running before it is closed
+	LeaseGracePeriod Duration
+	// billing of the deployments
+	Pricing PricingCfg
+}
+
+// PricingCfg configures the billing of deployments
+type PricingCfg struct {
+	// enables the billing of deployments, deployments without balance are then rejected
+	Enable bool
+	// interval between two debits of the deployment balances
+	BillingInterval Duration
+	// state of a deployment whose balance runs out, one of: inactive, close
+	ExhaustedState string
+	// hourly prices of the resources
+	Default ResourcePrice
+	// hourly prices overriding the default ones, keyed by provider id
+	Providers map[string]ResourcePrice
+}
+
+// ResourcePrice hourly prices of the resources
+type ResourcePrice struct {
+	// price of one cpu core per hour
+	CPU float64
+	// price of one GB of memory per hour
+	Memory float64
+	// price of one GB of storage per hour
+	Storage float64
 }
 
 // ProviderCfg provider config
@@ -33,4 +69,36 @@ type ProviderCfg struct {
 	PublicIP string
 
 	KubeConfigPath string
+
+	// kubernetes objects of the deployments, reloaded on every deployment
+	Kube KubeCfg
+}
+
+// KubeCfg configures the kubernetes objects generated for the deployments
+type KubeCfg struct {
+	// type of the services exposing the global ports, NodePort or LoadBalancer
+	ServiceType string
+	// generate a host under IngressDomain for the http ports without host
+	IngressStaticHosts bool
+	// domain the hosts of the deployments are generated under
+	IngressDomain string
+	// report the load balancer hosts of the ingresses with the hosts of the deployments
+	IngressExposeLBHosts bool
+	// cert-manager ClusterIssuer of the certificates of the ingress hosts, when the
+	// deployments do not supply theirs. Https is only available with supplied certificates when empty.
+	IngressClusterIssuer string
+	// hostname the global ports are reachable at, the PublicIP when empty
+	ClusterPublicHostname string
+	// isolate the deployments from each other with network policies
+	NetworkPoliciesEnabled bool
+	// overcommit factors, the pods request their limits divided by the factor.
+	// The full limits are requested when 1 or less
+	CPUCommitLevel     float64
+	GPUCommitLevel     float64
+	MemoryCommitLevel  float64
+	StorageCommitLevel float64
+	// runtime class of the pods, the default runtime of the cluster when empty
+	RuntimeClass string
+	// image pull secret of the namespace of the deployments used by the pods
+	ImagePullSecretsName string
 }
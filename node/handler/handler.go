@@ -2,9 +2,12 @@ package handler
 
 import (
 	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/filecoin-project/go-jsonrpc/auth"
 	logging "github.com/ipfs/go-log/v2"
-	"net/http"
 )
 
 var log = logging.Logger("handler")
@@ -12,11 +15,16 @@ var log = logging.Logger("handler")
 type (
 	// RemoteAddr client address
 	RemoteAddr struct{}
+	// Owner the owner carried by the request token
+	Owner struct{}
 )
 
+// VerifyPayloadFunc verifies a token and returns its content
+type VerifyPayloadFunc func(ctx context.Context, token string) (*types.JWTPayload, error)
+
 // Handler represents an HTTP handler that also adds remote client address and node ID to the request context
 type Handler struct {
-	handler *auth.Handler
+	handler http.Handler
 }
 
 // GetRemoteAddr returns the remote address of the client
@@ -28,11 +36,26 @@ func GetRemoteAddr(ctx context.Context) string {
 	return v
 }
 
+// GetOwner returns the owner the request token is bound to
+func GetOwner(ctx context.Context) string {
+	v, ok := ctx.Value(Owner{}).(string)
+	if !ok {
+		return ""
+	}
+	return v
+}
+
 // New returns a new HTTP handler with the given auth handler and additional request context fields
 func New(handler *auth.Handler) http.Handler {
 	return &Handler{handler: handler}
 }
 
+// NewWithOwner returns a new HTTP handler which verifies the request token, and adds its permissions,
+// its owner and the client remote address to the request context
+func NewWithOwner(verify VerifyPayloadFunc, next http.HandlerFunc) http.Handler {
+	return &Handler{handler: &ownerHandler{verify: verify, next: next}}
+}
+
 // ServeHTTP serves an HTTP request with the added client remote address and node ID in the request context
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	remoteAddr := r.Header.Get("X-Remote-Addr")
@@ -45,3 +68,42 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	h.handler.ServeHTTP(w, r.WithContext(ctx))
 }
+
+// ownerHandler works like auth.Handler, also keeping the owner of the token
+type ownerHandler struct {
+	verify VerifyPayloadFunc
+	next   http.HandlerFunc
+}
+
+func (h *ownerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		token = r.FormValue("token")
+		if token != "" {
+			token = "Bearer " + token
+		}
+	}
+
+	if token != "" {
+		if !strings.HasPrefix(token, "Bearer ") {
+			log.Warn("missing Bearer prefix in auth header")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		token = strings.TrimPrefix(token, "Bearer ")
+
+		payload, err := h.verify(ctx, token)
+		if err != nil {
+			log.Warnf("JWT Verification failed (originating from %s): %s", r.RemoteAddr, err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		ctx = auth.WithPerm(ctx, payload.Allow)
+		ctx = context.WithValue(ctx, Owner{}, payload.Owner)
+	}
+
+	h.next(w, r.WithContext(ctx))
+}
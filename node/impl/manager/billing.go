@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/node/config"
+	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
+	"go.uber.org/fx"
+)
+
+const (
+	ExhaustedStateInActive = "inactive"
+	ExhaustedStateClose    = "close"
+)
+
+// the unit of the memory and storage prices
+const priceUnitBytes = 1000000000
+
+// the amounts are stored with 6 decimals, see the DECIMAL columns of the balances and the ledger
+const amountScale = 1000000
+
+// roundAmount rounds the amount to the precision it is stored with, so the ledger records
+// exactly what is withdrawn from the balance
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*amountScale) / amountScale
+}
+
+// Billing periodically debits the balance of the active deployments with their hourly cost.
+type Billing struct {
+	db              *db.ManagerDB
+	providerManager *ProviderManager
+	getConfig       dtypes.GetManagerConfigFunc
+}
+
+func NewBilling(lc fx.Lifecycle, db *db.ManagerDB, pm *ProviderManager, getConfig dtypes.GetManagerConfigFunc) *Billing {
+	b := &Billing{
+		db:              db,
+		providerManager: pm,
+		getConfig:       getConfig,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go b.run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return b
+}
+
+func (b *Billing) run(ctx context.Context) {
+	for {
+		cfg, err := b.getConfig()
+		if err != nil {
+			log.Errorf("billing: get config: %v", err)
+		}
+
+		interval := time.Duration(cfg.Pricing.BillingInterval)
+		if interval <= 0 {
+			interval = time.Hour
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+
+		if err != nil || !cfg.Pricing.Enable {
+			continue
+		}
+
+		b.debit(ctx, &cfg.Pricing)
+	}
+}
+
+func (b *Billing) debit(ctx context.Context, pricing *config.PricingCfg) {
+	deployments, err := b.db.GetAllDeployments(ctx, []types.DeploymentState{types.DeploymentStateActive})
+	if err != nil {
+		log.Errorf("billing: get deployments: %v", err)
+		return
+	}
+
+	lastBilled, err := b.db.GetLastBilledTimes(ctx)
+	if err != nil {
+		log.Errorf("billing: get last billed times: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, deployment := range deployments {
+		start, ok := lastBilled[deployment.ID]
+		if !ok {
+			start = deployment.CreatedAt
+		}
+
+		if !start.Before(now) {
+			continue
+		}
+
+		cost := DeploymentCost(pricing, deployment)
+		entry := &types.LedgerEntry{
+			DeploymentID: deployment.ID,
+			Owner:        deployment.Owner,
+			Amount:       roundAmount(cost * now.Sub(start).Hours()),
+			StartTime:    start,
+			EndTime:      now,
+			CreatedAt:    now,
+		}
+
+		err = b.db.DebitDeployment(ctx, entry, cost)
+		if err != nil {
+			log.Errorf("billing: debit deployment %s: %v", deployment.ID, err)
+			continue
+		}
+
+		if entry.Balance > 0 {
+			continue
+		}
+
+		log.Warnf("balance of deployment %s ran out", deployment.ID)
+		if err := b.suspend(ctx, deployment, pricing.ExhaustedState); err != nil {
+			log.Errorf("billing: suspend deployment %s: %v", deployment.ID, err)
+		}
+	}
+}
+
+// suspend removes the workloads of a deployment which can not be paid anymore
+func (b *Billing) suspend(ctx context.Context, deployment *types.Deployment, exhaustedState string) error {
+	providerApi, err := b.providerManager.Get(deployment.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	err = providerApi.CloseDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
+	state := types.DeploymentStateInActive
+	if exhaustedState == ExhaustedStateClose {
+		state = types.DeploymentStateClose
+	}
+
+	return b.db.UpdateDeploymentState(ctx, deployment.ID, state)
+}
+
+// DeploymentCost returns the hourly cost of a deployment on its provider
+func DeploymentCost(pricing *config.PricingCfg, deployment *types.Deployment) float64 {
+	price := pricing.Default
+	if p, ok := pricing.Providers[string(deployment.ProviderID)]; ok {
+		price = p
+	}
+
+	resources := deploymentResources(deployment)
+	return resources.CPU*price.CPU +
+		float64(resources.Memory*resourceUnitBytes)/priceUnitBytes*price.Memory +
+		float64(resources.Storage*resourceUnitBytes)/priceUnitBytes*price.Storage
+}
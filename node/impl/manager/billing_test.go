@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentCost(t *testing.T) {
+	pricing := &config.PricingCfg{
+		Default: config.ResourcePrice{CPU: 1, Memory: 0.5, Storage: 0.1},
+		Providers: map[string]config.ResourcePrice{
+			"cheap": {CPU: 0.5},
+		},
+	}
+
+	deployment := &types.Deployment{
+		ProviderID: "default",
+		Services: []*types.Service{
+			{ComputeResources: types.ComputeResources{CPU: 1, Memory: 1000, Storage: 10000}},
+			{ComputeResources: types.ComputeResources{CPU: 0.5, Memory: 1000}},
+		},
+	}
+	require.InDelta(t, 1.5+1+1, DeploymentCost(pricing, deployment), 1e-9)
+
+	deployment.ProviderID = "cheap"
+	require.InDelta(t, 0.75, DeploymentCost(pricing, deployment), 1e-9)
+}
+
+func TestRoundAmount(t *testing.T) {
+	require.Equal(t, 0.000001, roundAmount(0.0000014))
+	require.Equal(t, 1.000002, roundAmount(1.0000015))
+
+	// hourly debits sum to the balance they are withdrawn from
+	balance := 10.0
+	for i := 0; i < 1000; i++ {
+		balance = roundAmount(balance - roundAmount(0.1/3))
+	}
+	require.Equal(t, roundAmount(10-1000*0.033333), balance)
+}
@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/journal/alerting"
+	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
+	"go.uber.org/fx"
+)
+
+var LeaseCheckInterval = time.Minute
+
+// LeaseManager closes the deployments whose lease expired, once the grace period is over.
+type LeaseManager struct {
+	db              *db.ManagerDB
+	providerManager *ProviderManager
+	getConfig       dtypes.GetManagerConfigFunc
+
+	alerting     *alerting.Alerting
+	expiredAlert alerting.AlertType
+}
+
+func NewLeaseManager(lc fx.Lifecycle, db *db.ManagerDB, pm *ProviderManager, al *alerting.Alerting, getConfig dtypes.GetManagerConfigFunc) *LeaseManager {
+	l := &LeaseManager{
+		db:              db,
+		providerManager: pm,
+		getConfig:       getConfig,
+		alerting:        al,
+		expiredAlert:    al.AddAlertType("manager", "lease-expired"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go l.run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return l
+}
+
+func (l *LeaseManager) run(ctx context.Context) {
+	ticker := time.NewTicker(LeaseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.checkLeases(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *LeaseManager) checkLeases(ctx context.Context) {
+	cfg, err := l.getConfig()
+	if err != nil {
+		log.Errorf("lease: get config: %v", err)
+		return
+	}
+
+	deployments, err := l.db.GetAllDeployments(ctx, []types.DeploymentState{types.DeploymentStateActive, types.DeploymentStateInActive})
+	if err != nil {
+		log.Errorf("lease: get deployments: %v", err)
+		return
+	}
+
+	now := time.Now()
+	grace := time.Duration(cfg.LeaseGracePeriod)
+
+	var expiring []string
+	for _, deployment := range deployments {
+		if deployment.Expiration.IsZero() || now.Before(deployment.Expiration) {
+			continue
+		}
+
+		deadline := deployment.Expiration.Add(grace)
+		if now.Before(deadline) {
+			log.Warnf("lease of deployment %s expired at %s, it will be closed at %s", deployment.ID, deployment.Expiration.Format(time.RFC3339), deadline.Format(time.RFC3339))
+			expiring = append(expiring, fmt.Sprintf("%s closes at %s", deployment.ID, deadline.Format(time.RFC3339)))
+			continue
+		}
+
+		if err := l.closeDeployment(ctx, deployment); err != nil {
+			log.Errorf("lease: close deployment %s: %v", deployment.ID, err)
+			continue
+		}
+
+		log.Infof("closed deployment %s, lease expired at %s", deployment.ID, deployment.Expiration.Format(time.RFC3339))
+	}
+
+	if len(expiring) > 0 {
+		l.alerting.Raise(l.expiredAlert, expiring)
+		return
+	}
+
+	for _, alert := range l.alerting.GetAlerts() {
+		if alert.Type == l.expiredAlert && alert.Active {
+			l.alerting.Resolve(l.expiredAlert, "no expired deployments")
+		}
+	}
+}
+
+func (l *LeaseManager) closeDeployment(ctx context.Context, deployment *types.Deployment) error {
+	providerApi, err := l.providerManager.Get(deployment.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	err = providerApi.CloseDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
+	return l.db.UpdateDeploymentState(ctx, deployment.ID, types.DeploymentStateClose)
+}
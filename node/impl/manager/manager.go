@@ -2,14 +2,17 @@ package manager
 
 import (
 	"context"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/lib/cryptobox"
 	"github.com/Filecoin-Titan/titan-container/node/handler"
 	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
+	"github.com/filecoin-project/go-jsonrpc/auth"
 	"github.com/google/uuid"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/pkg/errors"
@@ -18,6 +21,8 @@ import (
 
 var log = logging.Logger("manager")
 
+var ErrDeploymentNotFound = errors.New("deployment not found")
+
 // Manager represents a manager service in a cloud computing system.
 type Manager struct {
 	fx.In
@@ -26,9 +31,15 @@ type Manager struct {
 	DB *db.ManagerDB
 
 	ProviderManager *ProviderManager
+	Reconciler      *Reconciler
+	LeaseManager    *LeaseManager
+	Billing         *Billing
 
 	SetManagerConfigFunc dtypes.SetManagerConfigFunc
 	GetManagerConfigFunc dtypes.GetManagerConfigFunc
+
+	// seals the registry credentials and the secret env of the services in the database
+	CredentialsBox *cryptobox.Box
 }
 
 func (m *Manager) GetStatistics(ctx context.Context, id types.ProviderID) (*types.ResourcesStatistics, error) {
@@ -75,12 +86,26 @@ func (m *Manager) GetProviderList(ctx context.Context, opt *types.GetProviderOpt
 }
 
 func (m *Manager) GetDeploymentList(ctx context.Context, opt *types.GetDeploymentOption) ([]*types.Deployment, error) {
+	owner, err := callerOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner != "" {
+		opt.Owner = owner
+	}
+
 	deployments, err := m.DB.GetDeployments(ctx, opt)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, deployment := range deployments {
+		if err := openSecrets(m.CredentialsBox, deployment.Services); err != nil {
+			return nil, err
+		}
+		redactServices(deployment.Services)
+
 		providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 		if err != nil {
 			deployment.State = types.DeploymentStateInActive
@@ -92,6 +117,18 @@ func (m *Manager) GetDeploymentList(ctx context.Context, opt *types.GetDeploymen
 			continue
 		}
 
+		// the provider returns neither the secret env nor the files, keep the ones of the stored specs
+		stored := make(map[string]*types.Service, len(deployment.Services))
+		for _, service := range deployment.Services {
+			stored[service.Name] = service
+		}
+		for _, service := range remoteDeployment.Services {
+			if spec, ok := stored[service.Name]; ok {
+				service.SecretEnv = spec.SecretEnv
+				service.Files = spec.Files
+			}
+		}
+
 		deployment.Services = remoteDeployment.Services
 	}
 
@@ -99,77 +136,425 @@ func (m *Manager) GetDeploymentList(ctx context.Context, opt *types.GetDeploymen
 }
 
 func (m *Manager) CreateDeployment(ctx context.Context, deployment *types.Deployment) error {
+	owner := handler.GetOwner(ctx)
+	if owner == "" && !auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return ErrNoOwner
+	}
+
+	// the owner of the token wins over the one of the request, only admins can create deployments for anyone
+	if owner != "" {
+		deployment.Owner = owner
+	}
+
+	// the id is not signed, it is recorded with the nonce of the signature
+	deployment.ID = types.DeploymentID(uuid.New().String())
+	err := m.verifySignature(ctx, deployment, types.SignActionCreate)
+	if err != nil {
+		return err
+	}
+
+	if deployment.Authority && !auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return errors.New("only admins can deploy from authority")
+	}
+
+	setServiceReplicas(deployment.Services, nil)
+
+	if deployment.ProviderID == "" {
+		providerID, err := m.selectProvider(ctx, deployment)
+		if err != nil {
+			return err
+		}
+
+		log.Infof("select provider %s for deployment %s", providerID, deployment.Name)
+		deployment.ProviderID = providerID
+	}
+
 	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return err
 	}
 
-	// TODO: authority validation
+	err = m.setDeploymentExpiration(deployment)
+	if err != nil {
+		return err
+	}
+
+	err = m.setDeploymentCost(deployment)
+	if err != nil {
+		return err
+	}
 
-	deployment.ID = types.DeploymentID(uuid.New().String())
 	deployment.State = types.DeploymentStateActive
 	deployment.CreatedAt = time.Now()
 	deployment.UpdatedAt = time.Now()
+	assignServiceNames(deployment.Services)
+
+	err = m.fundDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
 
 	err = providerApi.CreateDeployment(ctx, deployment)
+	if err == nil {
+		err = m.saveDeployment(ctx, providerApi, deployment, "")
+	}
 	if err != nil {
+		m.refundDeployment(ctx, deployment)
 		return err
 	}
 
-	successDeployment, err := providerApi.GetDeployment(ctx, deployment.ID)
+	return nil
+}
+
+func (m *Manager) UpdateDeployment(ctx context.Context, deployment *types.Deployment) error {
+	existing, err := m.getCallerDeployment(ctx, deployment.ID)
 	if err != nil {
 		return err
 	}
 
-	deployment.Services = successDeployment.Services
-	for _, service := range deployment.Services {
-		service.DeploymentID = deployment.ID
-		service.CreatedAt = time.Now()
-		service.UpdatedAt = time.Now()
+	deployment.Owner = existing.Owner
+	err = m.verifySignature(ctx, deployment, types.SignActionUpdate)
+	if err != nil {
+		return err
+	}
+
+	if deployment.Authority != existing.Authority && !auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return errors.New("only admins can change the authority of a deployment")
+	}
+
+	// the placement, the ownership and the lease of a deployment are not updatable
+	deployment.ProviderID = existing.ProviderID
+	deployment.State = existing.State
+	deployment.Balance = existing.Balance
+	deployment.Cost = existing.Cost
+	deployment.Expiration = existing.Expiration
+	deployment.CreatedAt = existing.CreatedAt
+	deployment.UpdatedAt = time.Now()
+	assignServiceNames(deployment.Services)
+	setServiceReplicas(deployment.Services, existing.Services)
+	if err := checkVolumeChanges(deployment.Services, existing.Services); err != nil {
+		return err
 	}
 
-	err = m.DB.CreateDeployment(ctx, deployment)
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if err := m.checkServicesChange(ctx, providerApi, deployment, existing.Services); err != nil {
+		return err
+	}
+	if err := openSecrets(m.CredentialsBox, existing.Services); err != nil {
+		return err
+	}
+	if err := restoreRedacted(deployment.Services, existing.Services); err != nil {
+		return err
+	}
+
+	err = providerApi.UpdateDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
+	return m.saveDeployment(ctx, providerApi, deployment, "")
 }
 
-func (m *Manager) UpdateDeployment(ctx context.Context, deployment *types.Deployment) error {
+func (m *Manager) CloseDeployment(ctx context.Context, deployment *types.Deployment) error {
+	existing, err := m.getCallerDeployment(ctx, deployment.ID)
+	if err != nil {
+		return err
+	}
+
+	signature := deployment.Signature
+	deployment = existing
+	deployment.Signature = signature
+
+	err = m.verifySignature(ctx, deployment, types.SignActionClose)
+	if err != nil {
+		return err
+	}
+
 	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return err
 	}
 
-	err = providerApi.CreateDeployment(ctx, deployment)
+	err = providerApi.CloseDeployment(ctx, deployment)
 	if err != nil {
 		return err
 	}
 
-	err = m.DB.CreateDeployment(ctx, deployment)
+	return m.DB.CloseDeployment(ctx, deployment.ID, deployment.Signature)
+}
+
+// ScaleDeployment changes the number of pods of a service, the service may be omitted when the deployment has a single one
+func (m *Manager) ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error {
+	if replicas < 1 {
+		return errors.Errorf("invalid replicas %d, a service runs at least one pod", replicas)
+	}
+
+	deployment, err := m.getCallerDeployment(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if deployment.State == types.DeploymentStateClose {
+		return errors.Errorf("deployment %s is closed", id)
+	}
+
+	if service == "" && len(deployment.Services) == 1 {
+		service = deployment.Services[0].Name
+	}
+
+	var target *types.Service
+	current := make([]*types.Service, 0, len(deployment.Services))
+	for _, s := range deployment.Services {
+		if s.Name == service {
+			target = s
+		}
+		previous := *s
+		current = append(current, &previous)
+	}
+
+	if target == nil {
+		return errors.Errorf("service %q not found in deployment %s", service, id)
+	}
+
+	if target.Autoscale != nil {
+		return errors.Errorf("service %s is autoscaled, update its autoscale bounds instead", service)
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	target.Replicas = replicas
+	if err := m.checkServicesChange(ctx, providerApi, deployment, current); err != nil {
+		return err
+	}
+
+	err = providerApi.ScaleDeployment(ctx, deployment.ID, service, replicas)
+	if err != nil {
+		return err
+	}
+
+	return m.DB.UpdateServiceReplicas(ctx, deployment.ID, service, replicas, deployment.Cost)
 }
 
-func (m *Manager) CloseDeployment(ctx context.Context, deployment *types.Deployment) error {
+// RestartDeployment restarts the pods of a service, or of every service when empty, keeping the
+// deployment and its exposed ports. It returns once the rollout completed.
+func (m *Manager) RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if deployment.State == types.DeploymentStateClose {
+		return errors.Errorf("deployment %s is closed", id)
+	}
+
+	if service != "" {
+		found := false
+		for _, s := range deployment.Services {
+			found = found || s.Name == service
+		}
+		if !found {
+			return errors.Errorf("service %q not found in deployment %s", service, id)
+		}
+	}
+
 	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return err
 	}
 
-	err = providerApi.CloseDeployment(ctx, deployment)
+	return providerApi.RestartDeployment(ctx, deployment.ID, service)
+}
+
+func (m *Manager) RenewDeployment(ctx context.Context, id types.DeploymentID, duration time.Duration) error {
+	if duration <= 0 {
+		return errors.Errorf("invalid lease duration %s", duration)
+	}
+
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if deployment.State == types.DeploymentStateClose {
+		return errors.Errorf("deployment %s is closed", id)
+	}
+
+	expiration := deployment.Expiration
+	if expiration.Before(time.Now()) {
+		expiration = time.Now()
+	}
+
+	return m.DB.UpdateDeploymentExpiration(ctx, id, expiration.Add(duration))
+}
+
+func (m *Manager) setDeploymentExpiration(deployment *types.Deployment) error {
+	if !deployment.Expiration.IsZero() {
+		if deployment.Expiration.Before(time.Now()) {
+			return errors.Errorf("expiration %s is in the past", deployment.Expiration.Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	cfg, err := m.GetManagerConfigFunc()
 	if err != nil {
 		return err
 	}
 
-	return m.DB.UpdateDeploymentState(ctx, deployment.ID, types.DeploymentStateClose)
+	if cfg.DefaultLeaseDuration <= 0 {
+		return errors.New("deployment expiration is required")
+	}
+
+	deployment.Expiration = time.Now().Add(time.Duration(cfg.DefaultLeaseDuration))
+	return nil
+}
+
+// setServiceReplicas defaults the unset replicas of the services to the ones of the current
+// services with the same name, so updates keep the scale of the deployment
+func setServiceReplicas(services []*types.Service, current []*types.Service) {
+	replicas := make(map[string]int, len(current))
+	for _, service := range current {
+		replicas[service.Name] = service.Replicas
+	}
+
+	for _, service := range services {
+		if service.Replicas > 0 {
+			continue
+		}
+
+		service.Replicas = 1
+		if r, ok := replicas[service.Name]; ok && r > 0 {
+			service.Replicas = r
+		}
+	}
+}
+
+// checkServicesChange checks the services of the deployment which replace the current ones: their
+// replicas are bounded, the resources they add fit the provider, and the balance of the deployment
+// covers their cost when it increases. The cost of the deployment is set to the one of the services.
+func (m *Manager) checkServicesChange(ctx context.Context, providerApi api.Provider, deployment *types.Deployment, current []*types.Service) error {
+	for _, service := range deployment.Services {
+		if service.Replicas > types.MaxReplicas {
+			return errors.Errorf("invalid replicas %d of service %s, a service runs at most %d pods", service.Replicas, service.Name, types.MaxReplicas)
+		}
+		if service.Autoscale != nil && service.Autoscale.MaxReplicas > types.MaxReplicas {
+			return errors.Errorf("invalid max replicas %d of service %s, a service runs at most %d pods", service.Autoscale.MaxReplicas, service.Name, types.MaxReplicas)
+		}
+	}
+
+	// only the resources added to the current services are claimed on the provider
+	previous := deploymentResources(&types.Deployment{Services: current})
+	next := deploymentResources(deployment)
+	added := &types.ComputeResources{}
+	if next.CPU > previous.CPU {
+		added.CPU = next.CPU - previous.CPU
+	}
+	if next.Memory > previous.Memory {
+		added.Memory = next.Memory - previous.Memory
+	}
+	if next.Storage > previous.Storage {
+		added.Storage = next.Storage - previous.Storage
+	}
+	if err := checkProviderCapacity(ctx, providerApi, added); err != nil {
+		return err
+	}
+
+	// lowering the cost is always allowed
+	cost := deployment.Cost
+	if err := m.setDeploymentCost(deployment); err != nil {
+		return err
+	}
+	if deployment.Cost > cost && deployment.Cost > deployment.Balance {
+		return errors.Errorf("the balance %f of deployment %s does not cover its new cost %f", deployment.Balance, deployment.ID, deployment.Cost)
+	}
+
+	return nil
+}
+
+// checkVolumeChanges rejects the changes of the volumes of the services which keep volumes, the
+// volume claim templates of their stateful sets are immutable. The volumes can be added to or
+// removed from a service as a whole, it then runs in a new workload.
+func checkVolumeChanges(services []*types.Service, current []*types.Service) error {
+	volumes := make(map[string]types.Volumes, len(current))
+	for _, service := range current {
+		volumes[service.Name] = service.Volumes
+	}
+
+	for _, service := range services {
+		currentVolumes := volumes[service.Name]
+		if len(service.Volumes) == 0 || len(currentVolumes) == 0 {
+			continue
+		}
+
+		if !equalSpec(service.Volumes, currentVolumes) {
+			return errors.Errorf("the volumes of service %s can not be changed, remove them all first", service.Name)
+		}
+	}
+	return nil
+}
+
+// setDeploymentCost computes the hourly cost of the services of the deployment when billing is enabled
+func (m *Manager) setDeploymentCost(deployment *types.Deployment) error {
+	cfg, err := m.GetManagerConfigFunc()
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Pricing.Enable {
+		return nil
+	}
+
+	deployment.Cost = DeploymentCost(&cfg.Pricing, deployment)
+	return nil
+}
+
+// fundDeployment draws the balance of a new deployment from the credit of its owner, the
+// balance of the request is only the amount to draw
+func (m *Manager) fundDeployment(ctx context.Context, deployment *types.Deployment) error {
+	cfg, err := m.GetManagerConfigFunc()
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Pricing.Enable {
+		deployment.Balance = 0
+		return nil
+	}
+
+	if deployment.Balance <= 0 {
+		return errors.New("deployment balance must be positive")
+	}
+
+	err = m.DB.DebitAccount(ctx, deployment.Owner, deployment.Balance, time.Now())
+	if errors.Is(err, db.ErrInsufficientCredit) {
+		return errors.Errorf("the credit of %s does not cover a balance of %f", deployment.Owner, deployment.Balance)
+	}
+	return err
+}
+
+// refundDeployment gives the balance of a deployment which failed to be created back to its owner
+func (m *Manager) refundDeployment(ctx context.Context, deployment *types.Deployment) {
+	if deployment.Balance <= 0 {
+		return
+	}
+
+	if err := m.DB.CreditAccount(ctx, deployment.Owner, deployment.Balance, time.Now()); err != nil {
+		log.Errorf("refund deployment %s of %s: %v", deployment.ID, deployment.Owner, err)
+	}
 }
 
 func (m *Manager) GetLogs(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceLog, error) {
+	deployment, err := m.getCallerDeployment(ctx, deployment.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return nil, err
@@ -179,6 +564,11 @@ func (m *Manager) GetLogs(ctx context.Context, deployment *types.Deployment) ([]
 }
 
 func (m *Manager) GetEvents(ctx context.Context, deployment *types.Deployment) ([]*types.ServiceEvent, error) {
+	deployment, err := m.getCallerDeployment(ctx, deployment.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
 	if err != nil {
 		return nil, err
@@ -187,6 +577,93 @@ func (m *Manager) GetEvents(ctx context.Context, deployment *types.Deployment) (
 	return providerApi.GetEvents(ctx, deployment.ID)
 }
 
+func (m *Manager) GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerApi.GetDeploymentMetrics(ctx, deployment.ID)
+}
+
+func (m *Manager) WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerApi.WatchEvents(ctx, deployment.ID)
+}
+
+func (m *Manager) StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error) {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerApi.StreamLogs(ctx, deployment.ID, opts)
+}
+
+func (m *Manager) ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error) {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// hide the rpc stream from the encoder, so the data is proxied instead of the
+	// client being redirected to the provider, which it may not be able to reach
+	return providerApi.ExecDeployment(ctx, deployment.ID, opts, struct{ io.Reader }{stdin})
+}
+
+func (m *Manager) CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	// proxied like the exec stdin
+	return providerApi.CopyToDeployment(ctx, deployment.ID, opts, struct{ io.Reader }{archive})
+}
+
+func (m *Manager) CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error) {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return providerApi.CopyFromDeployment(ctx, deployment.ID, opts)
+}
+
 func (m *Manager) SetProperties(ctx context.Context, properties *types.Properties) error {
 	_, err := m.ProviderManager.Get(properties.ProviderID)
 	if err != nil {
@@ -198,4 +675,110 @@ func (m *Manager) SetProperties(ctx context.Context, properties *types.Propertie
 	return m.DB.AddProperties(ctx, properties)
 }
 
+func (m *Manager) AddCredit(ctx context.Context, owner string, amount float64) error {
+	if owner == "" {
+		return errors.New("owner can not be empty")
+	}
+	if amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+
+	return m.DB.CreditAccount(ctx, owner, amount, time.Now())
+}
+
+func (m *Manager) GetAccount(ctx context.Context, owner string) (*types.Account, error) {
+	caller, err := callerOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller != "" {
+		owner = caller
+	}
+
+	return m.DB.GetAccount(ctx, owner)
+}
+
+func (m *Manager) GetLedger(ctx context.Context, opt *types.GetLedgerOption) ([]*types.LedgerEntry, error) {
+	owner, err := callerOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner != "" {
+		opt.Owner = owner
+	}
+
+	return m.DB.GetLedger(ctx, opt)
+}
+
+// redactServices removes the private material of the services before they leave the manager
+func redactServices(services []*types.Service) {
+	for _, service := range services {
+		service.Credentials = nil
+		service.SealedCredentials = nil
+		service.SecretEnv = maskEnv(service.SecretEnv)
+		service.SealedSecretEnv = nil
+		service.SealedTLSKey = nil
+
+		if service.TLS != nil && service.TLS.Key != "" {
+			tls := *service.TLS
+			tls.Key = ""
+			service.TLS = &tls
+		}
+	}
+}
+
+// restoreRedacted puts back the private material of the current services, which the
+// updated specs miss when they were built from a redacted response. Services without
+// credentials keep the current ones, empty credentials remove them. Likewise, services
+// without secret env keep the current one and masked values are the current values. A
+// certificate without key keeps the current key when it is the current certificate.
+func restoreRedacted(services []*types.Service, current []*types.Service) error {
+	keys := make(map[string]*types.TLSConfig, len(current))
+	credentials := make(map[string]*types.RegistryCredentials, len(current))
+	secretEnv := make(map[string]types.Env, len(current))
+	for _, service := range current {
+		keys[service.Name] = service.TLS
+		credentials[service.Name] = service.Credentials
+		secretEnv[service.Name] = service.SecretEnv
+	}
+
+	for _, service := range services {
+		if service.SecretEnv == nil {
+			service.SecretEnv = secretEnv[service.Name]
+		} else {
+			for key, value := range service.SecretEnv {
+				if value != types.MaskedValue {
+					continue
+				}
+
+				currentValue, ok := secretEnv[service.Name][key]
+				if !ok {
+					return errors.Errorf("secret env %s of service %s has no value", key, service.Name)
+				}
+				service.SecretEnv[key] = currentValue
+			}
+		}
+
+		if service.Credentials == nil {
+			service.Credentials = credentials[service.Name]
+		} else if *service.Credentials == (types.RegistryCredentials{}) {
+			service.Credentials = nil
+		}
+
+		currentTLS := keys[service.Name]
+		if service.TLS == nil || service.TLS.Key != "" || service.TLS.Certificate == "" {
+			continue
+		}
+
+		if currentTLS == nil || service.TLS.Certificate != currentTLS.Certificate {
+			return errors.Errorf("the tls certificate of service %s has no key", service.Name)
+		}
+		service.TLS.Key = currentTLS.Key
+	}
+
+	return nil
+}
+
 var _ api.Manager = &Manager{}
@@ -0,0 +1,80 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api"
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/lib/signature"
+	"github.com/Filecoin-Titan/titan-container/node/handler"
+	"github.com/filecoin-project/go-jsonrpc/auth"
+	"github.com/pkg/errors"
+)
+
+var ErrNoOwner = errors.New("the token is not bound to an owner")
+
+// callerOwner returns the owner the caller is restricted to, admin callers are not restricted and get an empty owner
+func callerOwner(ctx context.Context) (string, error) {
+	if auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return "", nil
+	}
+
+	owner := handler.GetOwner(ctx)
+	if owner == "" {
+		return "", ErrNoOwner
+	}
+
+	return owner, nil
+}
+
+// getCallerDeployment loads a deployment from the database, making sure it belongs to the caller
+func (m *Manager) getCallerDeployment(ctx context.Context, id types.DeploymentID) (*types.Deployment, error) {
+	owner, err := callerOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := m.DB.GetDeployments(ctx, &types.GetDeploymentOption{DeploymentID: id, Owner: owner})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deployments) == 0 {
+		return nil, ErrDeploymentNotFound
+	}
+
+	return deployments[0], nil
+}
+
+// verifySignature checks the deployment was signed by its owner for the action, admins may send unsigned requests
+func (m *Manager) verifySignature(ctx context.Context, deployment *types.Deployment, action types.SignAction) error {
+	if deployment.Signature == nil && auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return nil
+	}
+
+	if err := signature.VerifyDeployment(deployment, action); err != nil {
+		return err
+	}
+
+	// a signed request is accepted once, it can not be replayed while its signature is valid
+	err := m.DB.UseSignatureNonce(ctx, deployment.ID, deployment.Owner, deployment.Signature, time.Now())
+	if errors.Is(err, db.ErrNonceUsed) {
+		return errors.Wrap(signature.ErrInvalidSignature, err.Error())
+	}
+	return err
+}
+
+// callerName identifies the caller as the author of a change
+func callerName(ctx context.Context) string {
+	if owner := handler.GetOwner(ctx); owner != "" {
+		return owner
+	}
+
+	if auth.HasPerm(ctx, nil, api.PermAdmin) {
+		return string(api.PermAdmin)
+	}
+
+	return ""
+}
@@ -0,0 +1,237 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/db"
+	"github.com/Filecoin-Titan/titan-container/journal/alerting"
+	"github.com/Filecoin-Titan/titan-container/lib/cryptobox"
+	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
+	"go.uber.org/fx"
+)
+
+const (
+	// ReconcileModeReport only logs and alerts on drift
+	ReconcileModeReport = "report"
+	// ReconcileModeRepair fixes the drift where possible
+	ReconcileModeRepair = "repair"
+)
+
+// Reconciler periodically compares the deployments in the manager database with
+// the workloads that are actually running on the providers.
+type Reconciler struct {
+	db              *db.ManagerDB
+	providerManager *ProviderManager
+	getConfig       dtypes.GetManagerConfigFunc
+	credentialsBox  *cryptobox.Box
+
+	alerting   *alerting.Alerting
+	driftAlert alerting.AlertType
+}
+
+// driftReport describes a difference between the manager database and a provider
+type driftReport struct {
+	ProviderID   types.ProviderID
+	DeploymentID types.DeploymentID
+	Problem      string
+}
+
+func NewReconciler(lc fx.Lifecycle, db *db.ManagerDB, pm *ProviderManager, al *alerting.Alerting, getConfig dtypes.GetManagerConfigFunc, box *cryptobox.Box) *Reconciler {
+	r := &Reconciler{
+		db:              db,
+		providerManager: pm,
+		getConfig:       getConfig,
+		credentialsBox:  box,
+		alerting:        al,
+		driftAlert:      al.AddAlertType("manager", "deployment-drift"),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go r.run(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+
+	return r
+}
+
+func (r *Reconciler) run(ctx context.Context) {
+	for {
+		cfg, err := r.getConfig()
+		if err != nil {
+			log.Errorf("reconciler: get config: %v", err)
+			cfg.ReconcileInterval = 0
+		}
+
+		interval := time.Duration(cfg.ReconcileInterval)
+		if interval <= 0 {
+			// disabled, check again later in case the config changes
+			interval = time.Minute
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+
+		if cfg.ReconcileInterval <= 0 {
+			continue
+		}
+
+		r.reconcile(ctx, cfg.ReconcileMode == ReconcileModeRepair)
+	}
+}
+
+func (r *Reconciler) reconcile(ctx context.Context, repair bool) {
+	deployments, err := r.db.GetAllDeployments(ctx, []types.DeploymentState{types.DeploymentStateActive, types.DeploymentStateInActive})
+	if err != nil {
+		log.Errorf("reconciler: get deployments: %v", err)
+		return
+	}
+
+	providerDeployments := make(map[types.ProviderID][]*types.Deployment)
+	for _, deployment := range deployments {
+		providerDeployments[deployment.ProviderID] = append(providerDeployments[deployment.ProviderID], deployment)
+	}
+
+	var reports []driftReport
+	for id, providerApi := range r.providerManager.GetAll() {
+		reports = append(reports, r.reconcileProvider(ctx, id, providerApi, providerDeployments[id], repair)...)
+	}
+
+	if len(reports) == 0 {
+		if r.isAlertActive() {
+			r.alerting.Resolve(r.driftAlert, "no deployment drift")
+		}
+		return
+	}
+
+	for _, report := range reports {
+		log.Warnw("deployment drift", "ProviderID", report.ProviderID, "DeploymentID", report.DeploymentID, "problem", report.Problem, "repair", repair)
+	}
+	r.alerting.Raise(r.driftAlert, reports)
+}
+
+func (r *Reconciler) reconcileProvider(ctx context.Context, id types.ProviderID, providerApi providerDeploymentAPI, deployments []*types.Deployment, repair bool) []driftReport {
+	var reports []driftReport
+
+	ids, err := providerApi.ListDeploymentIDs(ctx)
+	if err != nil {
+		log.Errorf("reconciler: list deployments of provider %s: %v", id, err)
+		return nil
+	}
+
+	namespaces := make(map[types.DeploymentID]struct{}, len(ids))
+	for _, deploymentID := range ids {
+		namespaces[deploymentID] = struct{}{}
+	}
+
+	known := make(map[types.DeploymentID]struct{}, len(deployments))
+	for _, deployment := range deployments {
+		known[deployment.ID] = struct{}{}
+
+		if deployment.State != types.DeploymentStateActive {
+			continue
+		}
+
+		if _, ok := namespaces[deployment.ID]; !ok {
+			reports = append(reports, driftReport{ProviderID: id, DeploymentID: deployment.ID, Problem: "namespace disappeared"})
+
+			if repair {
+				if err := r.db.UpdateDeploymentState(ctx, deployment.ID, types.DeploymentStateInActive); err != nil {
+					log.Errorf("reconciler: mark deployment %s inactive: %v", deployment.ID, err)
+				}
+			}
+			continue
+		}
+
+		report, err := r.reconcileDeployment(ctx, providerApi, deployment, repair)
+		if err != nil {
+			log.Errorf("reconciler: deployment %s: %v", deployment.ID, err)
+		}
+
+		if report != nil {
+			report.ProviderID = id
+			reports = append(reports, *report)
+		}
+	}
+
+	for _, deploymentID := range ids {
+		if _, ok := known[deploymentID]; !ok {
+			reports = append(reports, driftReport{ProviderID: id, DeploymentID: deploymentID, Problem: "namespace unknown to the manager"})
+		}
+	}
+
+	return reports
+}
+
+func (r *Reconciler) reconcileDeployment(ctx context.Context, providerApi providerDeploymentAPI, deployment *types.Deployment, repair bool) (*driftReport, error) {
+	remote, err := providerApi.GetDeployment(ctx, deployment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]struct{}, len(remote.Services))
+	for _, service := range remote.Services {
+		running[service.Name] = struct{}{}
+	}
+
+	var missing []string
+	for _, service := range deployment.Services {
+		if _, ok := running[service.Name]; !ok {
+			missing = append(missing, service.Name)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	report := &driftReport{DeploymentID: deployment.ID, Problem: fmt.Sprintf("missing workloads %v", missing)}
+	if !repair {
+		return report, nil
+	}
+
+	if err := openSecrets(r.credentialsBox, deployment.Services); err != nil {
+		return report, err
+	}
+
+	if len(remote.Services) == 0 {
+		err = providerApi.CreateDeployment(ctx, deployment)
+	} else {
+		err = providerApi.UpdateDeployment(ctx, deployment)
+	}
+	if err != nil {
+		return report, fmt.Errorf("re-apply workloads: %w", err)
+	}
+
+	log.Infof("reconciler: re-applied workloads %v of deployment %s", missing, deployment.ID)
+	return report, nil
+}
+
+func (r *Reconciler) isAlertActive() bool {
+	for _, alert := range r.alerting.GetAlerts() {
+		if alert.Type == r.driftAlert {
+			return alert.Active
+		}
+	}
+	return false
+}
+
+// providerDeploymentAPI is the part of the provider api used by the reconciler
+type providerDeploymentAPI interface {
+	GetDeployment(ctx context.Context, id types.DeploymentID) (*types.Deployment, error)
+	ListDeploymentIDs(ctx context.Context) ([]types.DeploymentID, error)
+	CreateDeployment(ctx context.Context, deployment *types.Deployment) error
+	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error
+}
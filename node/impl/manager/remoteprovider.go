@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"net/http"
+	"net/url"
 
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/api/client"
@@ -24,7 +25,8 @@ func connectRemoteProvider(ctx context.Context, fa api.Common, url string) (*rem
 	headers := http.Header{}
 	headers.Add("Authorization", "Bearer "+string(token))
 
-	papi, closer, err := client.NewProvider(context.TODO(), url, headers)
+	// websocket connections are needed for the methods returning channels
+	papi, closer, err := client.NewProvider(context.TODO(), websocketURL(url), headers)
 	if err != nil {
 		return nil, xerrors.Errorf("creating jsonrpc client: %w", err)
 	}
@@ -42,6 +44,21 @@ func connectRemoteProvider(ctx context.Context, fa api.Common, url string) (*rem
 	return &remoteProvider{papi, closer}, nil
 }
 
+func websocketURL(addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return addr
+	}
+
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	return u.String()
+}
+
 func (r *remoteProvider) Close() error {
 	r.closer()
 	return nil
@@ -0,0 +1,331 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api"
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var invalidServiceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func (m *Manager) GetDeploymentRevisions(ctx context.Context, id types.DeploymentID) ([]*types.DeploymentRevision, error) {
+	_, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := m.DB.GetDeploymentRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisions {
+		redactServices(revision.Services)
+	}
+
+	return revisions, nil
+}
+
+func (m *Manager) RollbackDeployment(ctx context.Context, id types.DeploymentID, revision int64, sig *types.Signature) error {
+	deployment, err := m.getCallerDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if sig != nil && sig.Revision != revision {
+		return errors.Errorf("the signature is for revision %d instead of %d", sig.Revision, revision)
+	}
+
+	deployment.Signature = sig
+	err = m.verifySignature(ctx, deployment, types.SignActionRollback)
+	if err != nil {
+		return err
+	}
+
+	if deployment.State == types.DeploymentStateClose {
+		return errors.Errorf("deployment %s is closed", id)
+	}
+
+	rev, err := m.DB.GetDeploymentRevision(ctx, id, revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.Errorf("revision %d of deployment %s not found", revision, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	providerApi, err := m.ProviderManager.Get(deployment.ProviderID)
+	if err != nil {
+		return err
+	}
+
+	current := deployment.Services
+	if err := openSecrets(m.CredentialsBox, current); err != nil {
+		return err
+	}
+
+	deployment.Services = rev.Services
+	deployment.UpdatedAt = time.Now()
+	setServiceReplicas(deployment.Services, current)
+	if err := checkVolumeChanges(deployment.Services, current); err != nil {
+		return err
+	}
+	if err := m.checkServicesChange(ctx, providerApi, deployment, current); err != nil {
+		return err
+	}
+	if err := restoreRedacted(deployment.Services, current); err != nil {
+		return err
+	}
+
+	err = providerApi.UpdateDeployment(ctx, deployment)
+	if err != nil {
+		return err
+	}
+
+	return m.saveDeployment(ctx, providerApi, deployment, fmt.Sprintf("rollback to revision %d", revision))
+}
+
+// saveDeployment stores the deployment once applied on the provider, and records its services in a new revision
+func (m *Manager) saveDeployment(ctx context.Context, providerApi api.Provider, deployment *types.Deployment, summary string) error {
+	remoteDeployment, err := providerApi.GetDeployment(ctx, deployment.ID)
+	if err != nil {
+		return err
+	}
+
+	latest, err := m.DB.GetLatestDeploymentRevision(ctx, deployment.ID)
+	if err != nil {
+		return err
+	}
+
+	revision := &types.DeploymentRevision{
+		DeploymentID: deployment.ID,
+		Revision:     1,
+		Author:       callerName(ctx),
+		Services:     serviceSpecs(deployment.Services),
+		Summary:      summary,
+		CreatedAt:    time.Now(),
+	}
+
+	if latest != nil {
+		revision.Revision = latest.Revision + 1
+	}
+
+	if revision.Summary == "" {
+		revision.Summary = "initial revision"
+		if latest != nil {
+			revision.Summary = revisionSummary(latest.Services, revision.Services)
+		}
+	}
+
+	deployment.Version = []byte(strconv.FormatInt(revision.Revision, 10))
+
+	setExposedPorts(deployment.Services, remoteDeployment.Services)
+	for _, service := range deployment.Services {
+		service.DeploymentID = deployment.ID
+		service.CreatedAt = time.Now()
+		service.UpdatedAt = time.Now()
+	}
+
+	if err := sealSecrets(m.CredentialsBox, deployment.Services); err != nil {
+		return err
+	}
+
+	return m.DB.CreateDeployment(ctx, deployment, revision)
+}
+
+// setExposedPorts copies the ports exposed by the provider to the ports of the services. The
+// ports requested by the owner are kept as they are, with their hosts and http options which
+// the provider does not report, only their expose port is taken from the provider port with
+// the same protocol and number.
+func setExposedPorts(services []*types.Service, remote []*types.Service) {
+	type portKey struct {
+		name     string
+		protocol types.Protocol
+		port     int
+	}
+
+	exposed := make(map[portKey]int)
+	for _, service := range remote {
+		for _, port := range service.Ports {
+			if port.ExposePort == 0 {
+				continue
+			}
+			exposed[portKey{service.Name, types.Protocol(strings.ToUpper(string(port.Protocol))), port.Port}] = port.ExposePort
+		}
+	}
+
+	for _, service := range services {
+		for i := range service.Ports {
+			port := &service.Ports[i]
+			port.ExposePort = exposed[portKey{service.Name, types.Protocol(strings.ToUpper(string(port.Protocol))), port.Port}]
+		}
+	}
+}
+
+// assignServiceNames names the services which have none, so they are applied to the same workloads on every revision
+func assignServiceNames(services []*types.Service) {
+	for _, service := range services {
+		if service.Name != "" {
+			continue
+		}
+
+		names := strings.Split(service.Image, "/")
+		name := strings.Split(names[len(names)-1], ":")[0]
+		name = strings.Trim(invalidServiceNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+		if len(name) > 40 {
+			name = name[:40]
+		}
+
+		service.Name = fmt.Sprintf("%s-%s", name, strings.ReplaceAll(uuid.NewString(), "-", "")[:8])
+	}
+}
+
+// serviceSpecs returns the services as requested by the owner, without what is set by the provider
+func serviceSpecs(services []*types.Service) types.ServiceSpecs {
+	specs := make(types.ServiceSpecs, 0, len(services))
+	for _, service := range services {
+		spec := *service
+		spec.Status = types.ReplicasStatus{}
+		spec.ErrorMessage = ""
+		spec.ID = 0
+		spec.DeploymentID = ""
+		spec.CreatedAt = time.Time{}
+		spec.UpdatedAt = time.Time{}
+		// revisions never hold credentials nor secret values, they are restored from the current services on rollback
+		spec.Credentials = nil
+		spec.SealedCredentials = nil
+		spec.SecretEnv = maskEnv(service.SecretEnv)
+		spec.SealedSecretEnv = nil
+		spec.SealedTLSKey = nil
+		if service.TLS != nil {
+			tls := *service.TLS
+			tls.Key = ""
+			spec.TLS = &tls
+		}
+
+		spec.Ports = make(types.Ports, 0, len(service.Ports))
+		for _, port := range service.Ports {
+			port.ExposePort = 0
+			spec.Ports = append(spec.Ports, port)
+		}
+
+		specs = append(specs, &spec)
+	}
+	return specs
+}
+
+// revisionSummary describes the changes between the services of two revisions
+func revisionSummary(previous, current types.ServiceSpecs) string {
+	old := make(map[string]*types.Service, len(previous))
+	for _, service := range previous {
+		old[service.Name] = service
+	}
+
+	var changes []string
+	for _, service := range current {
+		o, ok := old[service.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("add service %s (%s)", service.Name, service.Image))
+			continue
+		}
+		delete(old, service.Name)
+
+		if diff := serviceDiff(o, service); len(diff) > 0 {
+			changes = append(changes, fmt.Sprintf("%s: %s", service.Name, strings.Join(diff, ", ")))
+		}
+	}
+
+	for _, service := range previous {
+		if _, ok := old[service.Name]; ok {
+			changes = append(changes, fmt.Sprintf("remove service %s", service.Name))
+		}
+	}
+
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	return strings.Join(changes, "; ")
+}
+
+func serviceDiff(old, new *types.Service) []string {
+	var diff []string
+	if old.Image != new.Image {
+		diff = append(diff, fmt.Sprintf("image %s -> %s", old.Image, new.Image))
+	}
+	if old.CPU != new.CPU {
+		diff = append(diff, fmt.Sprintf("cpu %v -> %v", old.CPU, new.CPU))
+	}
+	if old.Memory != new.Memory {
+		diff = append(diff, fmt.Sprintf("memory %d -> %d", old.Memory, new.Memory))
+	}
+	if old.Storage != new.Storage {
+		diff = append(diff, fmt.Sprintf("storage %d -> %d", old.Storage, new.Storage))
+	}
+	if old.Replicas != new.Replicas {
+		diff = append(diff, fmt.Sprintf("replicas %d -> %d", old.Replicas, new.Replicas))
+	}
+	if !equalSpec(old.Autoscale, new.Autoscale) {
+		diff = append(diff, "autoscale changed")
+	}
+	if !equalSpec(old.LivenessProbe, new.LivenessProbe) || !equalSpec(old.ReadinessProbe, new.ReadinessProbe) || !equalSpec(old.StartupProbe, new.StartupProbe) {
+		diff = append(diff, "probes changed")
+	}
+	if !equalSpec(old.TLS, new.TLS) {
+		diff = append(diff, "tls changed")
+	}
+	if !equalSpec(old.Volumes, new.Volumes) {
+		diff = append(diff, "volumes changed")
+	}
+	if !equalSpec(old.Files, new.Files) {
+		diff = append(diff, "files changed")
+	}
+	if !equalSpec(old.Ports, new.Ports) {
+		diff = append(diff, "ports changed")
+	}
+	if !equalSpec(old.Env, new.Env) {
+		diff = append(diff, "env changed")
+	}
+	if !equalSpec(old.SecretEnv, new.SecretEnv) {
+		diff = append(diff, "secret env changed")
+	}
+	if !equalSpec(old.Command, new.Command) {
+		diff = append(diff, "command changed")
+	}
+	if !equalSpec(old.Arguments, new.Arguments) {
+		diff = append(diff, "arguments changed")
+	}
+
+	if len(diff) == 0 && !equalSpec(old, new) {
+		diff = append(diff, "settings changed")
+	}
+
+	return diff
+}
+
+// equalSpec compares two values, considering nil and empty as equal
+func equalSpec(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Map, reflect.Slice:
+		if va.Len() == 0 && vb.Len() == 0 {
+			return true
+		}
+	}
+
+	ja, _ := json.Marshal(a)
+	jb, _ := json.Marshal(b)
+	return bytes.Equal(ja, jb)
+}
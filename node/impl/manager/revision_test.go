@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevisionSummary(t *testing.T) {
+	previous := serviceSpecs([]*types.Service{
+		{Name: "web", Image: "nginx:1.24", ComputeResources: types.ComputeResources{CPU: 1}},
+		{Name: "db", Image: "mysql"},
+	})
+
+	require.Equal(t, "no changes", revisionSummary(previous, previous))
+
+	current := serviceSpecs([]*types.Service{
+		{Name: "web", Image: "nginx:1.25", ComputeResources: types.ComputeResources{CPU: 2}, Env: types.Env{"A": "1"}},
+		{Name: "cache", Image: "redis"},
+	})
+
+	require.Equal(t, "web: image nginx:1.24 -> nginx:1.25, cpu 1 -> 2, env changed; add service cache (redis); remove service db",
+		revisionSummary(previous, current))
+}
+
+func TestAssignServiceNames(t *testing.T) {
+	services := []*types.Service{{Image: "docker.io/Library/My_App:latest"}, {Name: "keep", Image: "nginx"}}
+	assignServiceNames(services)
+
+	require.Regexp(t, `^my-app-[0-9a-f]{8}$`, services[0].Name)
+	require.Equal(t, "keep", services[1].Name)
+}
+
+func TestRollbackTLSKey(t *testing.T) {
+	current := []*types.Service{{Name: "web", TLS: &types.TLSConfig{Certificate: "cert", Key: "key"}}}
+
+	specs := serviceSpecs(current)
+	require.Empty(t, specs[0].TLS.Key)
+	require.Equal(t, "key", current[0].TLS.Key)
+
+	services := []*types.Service(specs)
+	require.NoError(t, restoreRedacted(services, current))
+	require.Equal(t, "key", services[0].TLS.Key)
+
+	services = serviceSpecs([]*types.Service{{Name: "web", TLS: &types.TLSConfig{Certificate: "old"}}})
+	require.Error(t, restoreRedacted(services, current))
+}
+
+func TestSetExposedPorts(t *testing.T) {
+	services := []*types.Service{{
+		Name: "web",
+		Ports: types.Ports{
+			{Protocol: "tcp", Port: 80, Hosts: []string{"example.com"}, HTTPOptions: &types.HTTPOptions{MaxBodySize: 1024}},
+			{Protocol: types.TCP, Port: 6379},
+		},
+	}}
+
+	// the provider reports no hosts, the port 80 is routed by the ingress and has no node port
+	remote := []*types.Service{{
+		Name:  "web",
+		Ports: types.Ports{{Protocol: types.TCP, Port: 6379, ExposePort: 30001}, {Protocol: types.TCP, Port: 6379}},
+	}}
+
+	setExposedPorts(services, remote)
+
+	require.Equal(t, types.Ports{
+		{Protocol: "tcp", Port: 80, Hosts: []string{"example.com"}, HTTPOptions: &types.HTTPOptions{MaxBodySize: 1024}},
+		{Protocol: types.TCP, Port: 6379, ExposePort: 30001},
+	}, services[0].Ports)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/db"
 	"github.com/pkg/errors"
 )
 
@@ -21,11 +22,17 @@ var (
 type ProviderManager struct {
 	lk        sync.RWMutex
 	providers map[types.ProviderID]*providerLife
+
+	db *db.ManagerDB
 }
 
 type providerLife struct {
 	api.Provider
 	LastSeen time.Time
+
+	// guards State, which the watcher and AddProvider both update
+	stateLk sync.Mutex
+	State   types.ProviderState
 }
 
 func (p *providerLife) Update() {
@@ -39,9 +46,15 @@ func (p *providerLife) Expired() bool {
 	return false
 }
 
-func NewProviderScheduler() *ProviderManager {
+func NewProviderScheduler(db *db.ManagerDB) *ProviderManager {
 	s := &ProviderManager{
 		providers: make(map[types.ProviderID]*providerLife),
+		db:        db,
+	}
+
+	// no provider is connected yet, whatever the database says
+	if err := db.ResetProviderStates(context.Background(), types.ProviderStateOffline, time.Now()); err != nil {
+		log.Errorf("reset provider states: %v", err)
 	}
 
 	go s.watch()
@@ -50,17 +63,21 @@ func NewProviderScheduler() *ProviderManager {
 
 func (p *ProviderManager) AddProvider(id types.ProviderID, providerApi api.Provider) error {
 	p.lk.Lock()
-	p.lk.Unlock()
-
 	_, exist := p.providers[id]
 	if exist {
+		p.lk.Unlock()
 		return nil
 	}
 
-	p.providers[id] = &providerLife{
+	provider := &providerLife{
 		Provider: providerApi,
 		LastSeen: time.Now(),
 	}
+	p.providers[id] = provider
+	p.lk.Unlock()
+
+	// the state is persisted out of the providers lock, a slow database does not block the lookups
+	p.updateState(context.Background(), id, provider, types.ProviderStateOnline)
 	return nil
 }
 
@@ -77,6 +94,19 @@ func (p *ProviderManager) Get(id types.ProviderID) (api.Provider, error) {
 	return provider, nil
 }
 
+// GetAll returns a snapshot of all the online providers
+func (p *ProviderManager) GetAll() map[types.ProviderID]api.Provider {
+	p.lk.RLock()
+	defer p.lk.RUnlock()
+
+	out := make(map[types.ProviderID]api.Provider, len(p.providers))
+	for id, provider := range p.providers {
+		out[id] = provider
+	}
+
+	return out
+}
+
 func (p *ProviderManager) delProvider(id types.ProviderID) {
 	p.lk.Lock()
 	defer p.lk.Unlock()
@@ -86,6 +116,23 @@ func (p *ProviderManager) delProvider(id types.ProviderID) {
 	return
 }
 
+// updateState persists the provider state when it changes
+func (p *ProviderManager) updateState(ctx context.Context, id types.ProviderID, provider *providerLife, state types.ProviderState) {
+	provider.stateLk.Lock()
+	defer provider.stateLk.Unlock()
+
+	if provider.State == state {
+		return
+	}
+
+	log.Infow("provider state changed", "ProviderID", id, "from", types.ProviderStateString(provider.State), "to", types.ProviderStateString(state))
+	provider.State = state
+
+	if err := p.db.UpdateProviderState(ctx, id, state, time.Now()); err != nil {
+		log.Errorf("update provider %s state: %v", id, err)
+	}
+}
+
 func (p *ProviderManager) watch() {
 	heartbeatTimer := time.NewTicker(HeartbeatInterval)
 	defer heartbeatTimer.Stop()
@@ -98,26 +145,32 @@ func (p *ProviderManager) watch() {
 		case <-heartbeatTimer.C:
 		}
 
-		sctx, scancel := context.WithTimeout(ctx, HeartbeatInterval/2)
-
-		p.lk.Lock()
+		p.lk.RLock()
+		providers := make(map[types.ProviderID]*providerLife, len(p.providers))
 		for id, provider := range p.providers {
+			providers[id] = provider
+		}
+		p.lk.RUnlock()
+
+		for id, provider := range providers {
+			sctx, scancel := context.WithTimeout(ctx, HeartbeatInterval/2)
 			_, err := provider.Session(sctx)
 			scancel()
 			if err != nil {
 				if !provider.Expired() {
 					// Likely temporary error
 					log.Warnw("failed to check provider session", "error", err)
+					p.updateState(ctx, id, provider, types.ProviderStateAbnormal)
 					continue
 				}
 
 				log.Warnw("Provider closing", "ProviderID", id)
-				delete(p.providers, id)
+				p.delProvider(id)
+				p.updateState(ctx, id, provider, types.ProviderStateOffline)
 				continue
 			}
 			provider.Update()
+			p.updateState(ctx, id, provider, types.ProviderStateOnline)
 		}
-		p.lk.Unlock()
-
 	}
 }
@@ -0,0 +1,102 @@
+package manager
+
+import (
+	"encoding/json"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/lib/cryptobox"
+	"github.com/pkg/errors"
+)
+
+// sealSecrets seals the registry credentials, the secret env and the TLS key of the services before they are stored
+func sealSecrets(box *cryptobox.Box, services []*types.Service) error {
+	for _, service := range services {
+		service.SealedCredentials = nil
+		service.SealedSecretEnv = nil
+		service.SealedTLSKey = nil
+
+		if service.Credentials != nil {
+			sealed, err := sealJSON(box, service.Credentials)
+			if err != nil {
+				return errors.Errorf("seal credentials of service %s: %v", service.Name, err)
+			}
+			service.SealedCredentials = sealed
+		}
+
+		if len(service.SecretEnv) > 0 {
+			sealed, err := sealJSON(box, service.SecretEnv)
+			if err != nil {
+				return errors.Errorf("seal secret env of service %s: %v", service.Name, err)
+			}
+			service.SealedSecretEnv = sealed
+		}
+
+		if service.TLS != nil && service.TLS.Key != "" {
+			sealed, err := box.Seal([]byte(service.TLS.Key))
+			if err != nil {
+				return errors.Errorf("seal tls key of service %s: %v", service.Name, err)
+			}
+			service.SealedTLSKey = sealed
+		}
+	}
+	return nil
+}
+
+// openSecrets opens the stored registry credentials, secret env and TLS key of the services
+func openSecrets(box *cryptobox.Box, services []*types.Service) error {
+	for _, service := range services {
+		if len(service.SealedCredentials) > 0 {
+			credentials := &types.RegistryCredentials{}
+			if err := openJSON(box, service.SealedCredentials, credentials); err != nil {
+				return errors.Errorf("open credentials of service %s: %v", service.Name, err)
+			}
+			service.Credentials = credentials
+		}
+
+		if len(service.SealedSecretEnv) > 0 {
+			env := make(types.Env)
+			if err := openJSON(box, service.SealedSecretEnv, &env); err != nil {
+				return errors.Errorf("open secret env of service %s: %v", service.Name, err)
+			}
+			service.SecretEnv = env
+		}
+
+		if len(service.SealedTLSKey) > 0 && service.TLS != nil {
+			key, err := box.Open(service.SealedTLSKey)
+			if err != nil {
+				return errors.Errorf("open tls key of service %s: %v", service.Name, err)
+			}
+			service.TLS.Key = string(key)
+		}
+	}
+	return nil
+}
+
+func sealJSON(box *cryptobox.Box, v interface{}) ([]byte, error) {
+	plaintext, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return box.Seal(plaintext)
+}
+
+func openJSON(box *cryptobox.Box, sealed []byte, v interface{}) error {
+	plaintext, err := box.Open(sealed)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plaintext, v)
+}
+
+// maskEnv replaces the values of the secret env with MaskedValue
+func maskEnv(env types.Env) types.Env {
+	if env == nil {
+		return nil
+	}
+
+	masked := make(types.Env, len(env))
+	for key := range env {
+		masked[key] = types.MaskedValue
+	}
+	return masked
+}
@@ -0,0 +1,201 @@
+package manager
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Filecoin-Titan/titan-container/api"
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	StrategyLeastLoaded = "least-loaded"
+	StrategyBinPacking  = "bin-packing"
+)
+
+// the unit of service memory and storage, see resourceToManifestResource in the provider
+const resourceUnitBytes = 1000000
+
+var ErrNoProviderAvailable = errors.New("no provider can satisfy the deployment resources")
+
+// ScoreStrategy rates how suitable a provider is to host the requested resources, higher is better.
+type ScoreStrategy func(statistics *types.ResourcesStatistics, request *types.ComputeResources) float64
+
+var (
+	strategiesLk sync.RWMutex
+	strategies   = map[string]ScoreStrategy{
+		StrategyLeastLoaded: LeastLoaded,
+		StrategyBinPacking:  BinPacking,
+	}
+)
+
+// RegisterScoreStrategy makes a score strategy available under the given name
+func RegisterScoreStrategy(name string, strategy ScoreStrategy) {
+	strategiesLk.Lock()
+	defer strategiesLk.Unlock()
+
+	strategies[name] = strategy
+}
+
+func getScoreStrategy(name string) (ScoreStrategy, error) {
+	if name == "" {
+		name = StrategyLeastLoaded
+	}
+
+	strategiesLk.RLock()
+	defer strategiesLk.RUnlock()
+
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, errors.Errorf("unknown provider select strategy %s", name)
+	}
+	return strategy, nil
+}
+
+// LeastLoaded prefers the provider with the most free resources left after the deployment is placed.
+func LeastLoaded(statistics *types.ResourcesStatistics, request *types.ComputeResources) float64 {
+	return freeRatioAfter(statistics, request)
+}
+
+// BinPacking prefers the provider with the least free resources left after the deployment is placed,
+// keeping the other providers free for large deployments.
+func BinPacking(statistics *types.ResourcesStatistics, request *types.ComputeResources) float64 {
+	return 1 - freeRatioAfter(statistics, request)
+}
+
+func freeRatioAfter(statistics *types.ResourcesStatistics, request *types.ComputeResources) float64 {
+	ratio := func(available, request, max float64) float64 {
+		if max <= 0 {
+			return 0
+		}
+		return (available - request) / max
+	}
+
+	cpu := ratio(statistics.CPUCores.Available, request.CPU, statistics.CPUCores.MaxCPUCores)
+	memory := ratio(float64(statistics.Memory.Available), float64(request.Memory*resourceUnitBytes), float64(statistics.Memory.MaxMemory))
+	storage := ratio(float64(statistics.Storage.Available), float64(request.Storage*resourceUnitBytes), float64(statistics.Storage.MaxStorage))
+
+	return (cpu + memory + storage) / 3
+}
+
+func fits(statistics *types.ResourcesStatistics, request *types.ComputeResources) bool {
+	return statistics.CPUCores.Available >= request.CPU &&
+		statistics.Memory.Available >= uint64(request.Memory*resourceUnitBytes) &&
+		statistics.Storage.Available >= uint64(request.Storage*resourceUnitBytes)
+}
+
+func deploymentResources(deployment *types.Deployment) *types.ComputeResources {
+	total := &types.ComputeResources{}
+	for _, service := range deployment.Services {
+		replicas := serviceReplicas(service)
+		total.CPU += service.CPU * float64(replicas)
+		total.Memory += service.Memory * int64(replicas)
+		total.Storage += service.Storage * int64(replicas)
+		// every replica claims its own persistent volumes
+		for _, volume := range service.Volumes {
+			total.Storage += volume.Size * int64(replicas)
+		}
+	}
+	return total
+}
+
+// serviceReplicas returns the number of pods the service may run, one when unset.
+// Autoscaled services are placed and billed at their max replicas, which the
+// autoscaler can reach without the manager knowing.
+func serviceReplicas(service *types.Service) int {
+	replicas := service.Replicas
+	if service.Autoscale != nil {
+		replicas = service.Autoscale.MaxReplicas
+	}
+	if replicas <= 0 {
+		return 1
+	}
+	return replicas
+}
+
+// pickProvider returns the best scored provider which is able to fit the requested resources
+func pickProvider(candidates map[types.ProviderID]*types.ResourcesStatistics, request *types.ComputeResources, score ScoreStrategy) (types.ProviderID, error) {
+	type scored struct {
+		id    types.ProviderID
+		score float64
+	}
+
+	var list []scored
+	for id, statistics := range candidates {
+		if statistics == nil || !fits(statistics, request) {
+			continue
+		}
+		list = append(list, scored{id: id, score: score(statistics, request)})
+	}
+
+	if len(list) == 0 {
+		return "", ErrNoProviderAvailable
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].score == list[j].score {
+			return list[i].id < list[j].id
+		}
+		return list[i].score > list[j].score
+	})
+
+	return list[0].id, nil
+}
+
+// checkProviderCapacity checks the provider has the resources to run the requested ones
+// besides the ones already allocated. It passes when nothing more is requested.
+func checkProviderCapacity(ctx context.Context, providerApi api.Provider, request *types.ComputeResources) error {
+	if request.CPU <= 0 && request.Memory <= 0 && request.Storage <= 0 {
+		return nil
+	}
+
+	statistics, err := providerApi.GetStatistics(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !fits(statistics, request) {
+		return errors.Errorf("the provider can not satisfy %.2f cpu, %d memory and %d storage more", request.CPU, request.Memory, request.Storage)
+	}
+	return nil
+}
+
+func (m *Manager) selectProvider(ctx context.Context, deployment *types.Deployment) (types.ProviderID, error) {
+	cfg, err := m.GetManagerConfigFunc()
+	if err != nil {
+		return "", err
+	}
+
+	score, err := getScoreStrategy(cfg.ProviderSelectStrategy)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		lk         sync.Mutex
+		wg         sync.WaitGroup
+		candidates = make(map[types.ProviderID]*types.ResourcesStatistics)
+	)
+
+	for id, providerApi := range m.ProviderManager.GetAll() {
+		wg.Add(1)
+		go func(id types.ProviderID, providerApi api.Provider) {
+			defer wg.Done()
+
+			statistics, err := providerApi.GetStatistics(ctx)
+			if err != nil {
+				log.Warnf("get statistics of provider %s: %v", id, err)
+				return
+			}
+
+			lk.Lock()
+			candidates[id] = statistics
+			lk.Unlock()
+		}(id, providerApi)
+	}
+	wg.Wait()
+
+	return pickProvider(candidates, deploymentResources(deployment), score)
+}
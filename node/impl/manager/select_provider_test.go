@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatistics(cpu float64, memory, storage uint64) *types.ResourcesStatistics {
+	return &types.ResourcesStatistics{
+		CPUCores: types.CPUCores{MaxCPUCores: 8, Available: cpu},
+		Memory:   types.Memory{MaxMemory: 8000 * resourceUnitBytes, Available: memory * resourceUnitBytes},
+		Storage:  types.Storage{MaxStorage: 8000 * resourceUnitBytes, Available: storage * resourceUnitBytes},
+	}
+}
+
+func TestPickProvider(t *testing.T) {
+	candidates := map[types.ProviderID]*types.ResourcesStatistics{
+		"idle":  newStatistics(7, 7000, 7000),
+		"busy":  newStatistics(1, 1000, 1000),
+		"small": newStatistics(0.1, 100, 100),
+	}
+	request := &types.ComputeResources{CPU: 0.5, Memory: 500, Storage: 500}
+
+	id, err := pickProvider(candidates, request, LeastLoaded)
+	require.NoError(t, err)
+	require.Equal(t, types.ProviderID("idle"), id)
+
+	id, err = pickProvider(candidates, request, BinPacking)
+	require.NoError(t, err)
+	require.Equal(t, types.ProviderID("busy"), id)
+
+	_, err = pickProvider(candidates, &types.ComputeResources{CPU: 16}, LeastLoaded)
+	require.ErrorIs(t, err, ErrNoProviderAvailable)
+}
+
+func TestDeploymentResources(t *testing.T) {
+	deployment := &types.Deployment{
+		Services: []*types.Service{
+			{ComputeResources: types.ComputeResources{CPU: 0.1, Memory: 100, Storage: 200}},
+			{ComputeResources: types.ComputeResources{CPU: 0.2, Memory: 300, Storage: 400}, Replicas: 2},
+			{ComputeResources: types.ComputeResources{CPU: 0.1, Memory: 100, Storage: 100}, Replicas: 1, Autoscale: &types.AutoscalePolicy{MinReplicas: 1, MaxReplicas: 3}},
+		},
+	}
+
+	total := deploymentResources(deployment)
+	require.InDelta(t, 0.8, total.CPU, 1e-9)
+	require.Equal(t, int64(1000), total.Memory)
+	require.Equal(t, int64(1300), total.Storage)
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// CopyToDeployment extracts the tar archive into a directory of a pod of the service.
+// The tar command must be available in the container.
+func (m *manager) CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error {
+	if closer, ok := archive.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if opts.Path == "" {
+		return fmt.Errorf("path can not be empty")
+	}
+
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	podName, err := m.execPod(ctx, ns, opts.Service)
+	if err != nil {
+		return err
+	}
+
+	podExecOptions := &corev1.PodExecOptions{
+		Command: []string{"tar", "-x", "-m", "-f", "-", "-C", opts.Path},
+		Stdin:   true,
+		Stdout:  true,
+		Stderr:  true,
+	}
+
+	var stderr bytes.Buffer
+	err = m.kc.Exec(ctx, ns, podName, podExecOptions, remotecommand.StreamOptions{
+		Stdin:  archive,
+		Stdout: io.Discard,
+		Stderr: &stderr,
+	})
+
+	return tarError(err, &stderr)
+}
+
+// CopyFromDeployment streams a tar archive of a path of a pod of the service as the
+// stdout of the returned channel. The tar command must be available in the container.
+func (m *manager) CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("path can not be empty")
+	}
+
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	podName, err := m.execPod(ctx, ns, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	source := path.Clean(opts.Path)
+	podExecOptions := &corev1.PodExecOptions{
+		Command: []string{"tar", "-c", "-f", "-", "-C", path.Dir(source), path.Base(source)},
+		Stdout:  true,
+		Stderr:  true,
+	}
+
+	out := make(chan types.ExecOutput, execBufferSize)
+
+	go func() {
+		defer close(out)
+
+		var stderr bytes.Buffer
+		err := m.kc.Exec(ctx, ns, podName, podExecOptions, remotecommand.StreamOptions{
+			Stdout: &execWriter{ctx: ctx, out: out},
+			Stderr: &stderr,
+		})
+
+		result := types.ExecOutput{Exited: true}
+		if err := tarError(err, &stderr); err != nil {
+			result.ExitCode = 1
+			result.Error = err.Error()
+		}
+
+		select {
+		case out <- result:
+		case <-ctx.Done():
+		}
+	}()
+
+	return out, nil
+}
+
+// tarError adds the output of a failed tar command to its error
+func tarError(err error, stderr *bytes.Buffer) error {
+	if err == nil {
+		return nil
+	}
+
+	var exitErr exec.CodeExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("tar exited with code %d: %s", exitErr.ExitStatus(), strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
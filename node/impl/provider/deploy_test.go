@@ -16,7 +16,7 @@ import (
 
 func TestCreateDeploy(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	port := types.Port{Port: 6379}
@@ -33,7 +33,7 @@ func TestCreateDeploy(t *testing.T) {
 
 func TestUplodateDeploy(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	port := types.Port{Port: 6379}
@@ -67,7 +67,7 @@ func TestDeleteDeploy(t *testing.T) {
 
 func TestResourcesStatistics(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	statistics, err := manager.GetStatistics(context.Background())
@@ -79,7 +79,7 @@ func TestResourcesStatistics(t *testing.T) {
 
 func TestGetDeployment(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	deployment, err := manager.GetDeployment(context.Background(), types.DeploymentID("2222"))
@@ -113,7 +113,7 @@ func TestListDeployment(t *testing.T) {
 
 func TestGetLogs(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	logs, err := manager.GetLogs(context.Background(), types.DeploymentID("1111"))
@@ -137,7 +137,7 @@ func formatLogs(log string) []string {
 
 func TestGetEvents(t *testing.T) {
 	config := &config.ProviderCfg{KubeConfigPath: "./test/config", PublicIP: "192.168.0.132"}
-	manager, err := NewManager(config)
+	manager, err := NewManager(config, nil)
 	require.NoError(t, err)
 
 	events, err := manager.GetEvents(context.Background(), types.DeploymentID("2222"))
@@ -1,8 +1,13 @@
 package provider
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
@@ -10,10 +15,13 @@ import (
 	"github.com/google/uuid"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
-	podReplicas = 1
+	defaultReplicas = 1
+	defaultFileMode = 0644
 )
 
 func ClusterDeploymentFromDeployment(deployment *types.Deployment) (builder.IClusterDeployment, error) {
@@ -59,7 +67,13 @@ func serviceToManifestService(service *types.Service, Authority bool) (manifest.
 	if len(service.Image) == 0 {
 		return manifest.Service{}, fmt.Errorf("service image can not empty")
 	}
-	name := imageToServiceName(service.Image)
+	name := service.Name
+	if len(name) == 0 {
+		name = imageToServiceName(service.Image)
+	} else if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return manifest.Service{}, fmt.Errorf("invalid service name %s: %s", name, strings.Join(errs, ","))
+	}
+
 	resource := resourceToManifestResource(&service.ComputeResources)
 	exposes, err := exposesFromPorts(service.Ports)
 	if err != nil {
@@ -69,17 +83,270 @@ func serviceToManifestService(service *types.Service, Authority bool) (manifest.
 	s := manifest.Service{
 		Name:      name,
 		Image:     service.Image,
+		Command:   service.Command,
 		Args:      service.Arguments,
 		Env:       envToManifestEnv(service.Env),
 		Resources: &resource,
 		Expose:    make([]*manifest.ServiceExpose, 0),
-		Count:     podReplicas,
+		Count:     defaultReplicas,
+	}
+
+	if service.Replicas > types.MaxReplicas {
+		return manifest.Service{}, fmt.Errorf("service %s: replicas can not exceed %d", name, types.MaxReplicas)
+	}
+	if service.Replicas > 0 {
+		s.Count = int32(service.Replicas)
+	}
+
+	probes := []struct {
+		name   string
+		probe  *types.Probe
+		target **manifest.ServiceProbe
+	}{
+		{"liveness", service.LivenessProbe, &s.LivenessProbe},
+		{"readiness", service.ReadinessProbe, &s.ReadinessProbe},
+		{"startup", service.StartupProbe, &s.StartupProbe},
+	}
+	for _, p := range probes {
+		if p.probe == nil {
+			continue
+		}
+
+		probe, err := probeToManifestProbe(p.probe)
+		if err != nil {
+			return manifest.Service{}, fmt.Errorf("service %s %s probe: %w", name, p.name, err)
+		}
+		*p.target = probe
+	}
+
+	if service.Autoscale != nil {
+		autoscale, err := autoscaleToManifestAutoscale(service.Autoscale)
+		if err != nil {
+			return manifest.Service{}, fmt.Errorf("service %s: %w", name, err)
+		}
+		s.Autoscale = autoscale
+
+		// start within the bounds, the autoscaler takes over from there
+		if s.Count < autoscale.MinReplicas {
+			s.Count = autoscale.MinReplicas
+		}
+		if s.Count > autoscale.MaxReplicas {
+			s.Count = autoscale.MaxReplicas
+		}
+	}
+
+	if len(service.Volumes) > 0 {
+		storage, params, err := volumesToManifestStorage(service.Volumes)
+		if err != nil {
+			return manifest.Service{}, fmt.Errorf("service %s: %w", name, err)
+		}
+		s.Resources.Storage = append(s.Resources.Storage, storage...)
+		s.Params = params
 	}
 
 	if len(exposes) > 0 {
 		s.Expose = append(s.Expose, exposes...)
 	}
 
+	if service.Credentials != nil && *service.Credentials != (types.RegistryCredentials{}) {
+		if service.Credentials.Server == "" || service.Credentials.Username == "" {
+			return manifest.Service{}, fmt.Errorf("service %s: registry credentials require a server and a username", name)
+		}
+		s.Credentials = &manifest.ServiceCredentials{
+			Server:   service.Credentials.Server,
+			Username: service.Credentials.Username,
+			Password: service.Credentials.Password,
+		}
+	}
+
+	secretEnv, err := secretEnvToManifestSecretEnv(service.SecretEnv, service.Env)
+	if err != nil {
+		return manifest.Service{}, fmt.Errorf("service %s: %w", name, err)
+	}
+	s.SecretEnv = secretEnv
+
+	files, err := filesToManifestFiles(service.Files)
+	if err != nil {
+		return manifest.Service{}, fmt.Errorf("service %s: %w", name, err)
+	}
+	s.Files = files
+
+	if service.TLS != nil {
+		serviceTLS, err := tlsToManifestTLS(service.TLS, service.Ports)
+		if err != nil {
+			return manifest.Service{}, fmt.Errorf("service %s: %w", name, err)
+		}
+		s.TLS = serviceTLS
+	}
+
+	return s, nil
+}
+
+// tlsToManifestTLS checks the supplied certificate matches its key, it is issued by
+// the provider when both are empty
+func tlsToManifestTLS(config *types.TLSConfig, ports types.Ports) (*manifest.ServiceTLS, error) {
+	hasHTTP := false
+	for _, port := range ports {
+		if port.Port == 80 && (port.Protocol == "" || port.Protocol == types.TCP) {
+			hasHTTP = true
+		}
+	}
+	if !hasHTTP {
+		return nil, fmt.Errorf("tls requires an http port 80")
+	}
+
+	if len(config.Certificate) == 0 && len(config.Key) == 0 {
+		return &manifest.ServiceTLS{}, nil
+	}
+
+	if _, err := tls.X509KeyPair([]byte(config.Certificate), []byte(config.Key)); err != nil {
+		return nil, fmt.Errorf("invalid tls certificate: %w", err)
+	}
+
+	return &manifest.ServiceTLS{Certificate: []byte(config.Certificate), Key: []byte(config.Key)}, nil
+}
+
+// volumesToManifestStorage maps the volumes to persistent storage, the service is then
+// deployed as a stateful set with a volume claim per volume and replica
+func volumesToManifestStorage(volumes types.Volumes) ([]*manifest.Storage, *manifest.ServiceParams, error) {
+	storage := make([]*manifest.Storage, 0, len(volumes))
+	params := &manifest.ServiceParams{Storage: make([]manifest.StorageParams, 0, len(volumes))}
+
+	names := make(map[string]struct{})
+	mounts := make(map[string]struct{})
+	for _, volume := range volumes {
+		if errs := validation.IsDNS1123Label(volume.Name); len(errs) > 0 {
+			return nil, nil, fmt.Errorf("invalid volume name %s: %s", volume.Name, strings.Join(errs, ","))
+		}
+		if volume.Name == builder.FilesVolumeName {
+			return nil, nil, fmt.Errorf("volume name %s is reserved for the files", volume.Name)
+		}
+		if _, ok := names[volume.Name]; ok {
+			return nil, nil, fmt.Errorf("duplicate volume %s", volume.Name)
+		}
+		names[volume.Name] = struct{}{}
+
+		if volume.Size <= 0 {
+			return nil, nil, fmt.Errorf("volume %s size must be greater than 0", volume.Name)
+		}
+
+		mount := path.Clean(volume.Mount)
+		if !path.IsAbs(mount) || mount == "/" {
+			return nil, nil, fmt.Errorf("volume %s mount path must be an absolute path other than /", volume.Name)
+		}
+		if _, ok := mounts[mount]; ok {
+			return nil, nil, fmt.Errorf("volume %s mount path %s is already used", volume.Name, mount)
+		}
+		mounts[mount] = struct{}{}
+
+		class := volume.StorageClass
+		if len(class) == 0 {
+			class = builder.StorageClassDefault
+		}
+
+		storage = append(storage, &manifest.Storage{
+			Name:     volume.Name,
+			Quantity: manifest.NewResourceValue(uint64(volume.Size * 1000000)),
+			Attributes: manifest.Attributes{
+				{Key: builder.StorageAttributePersistent, Value: "true"},
+				{Key: builder.StorageAttributeClass, Value: class},
+			},
+		})
+		params.Storage = append(params.Storage, manifest.StorageParams{
+			Name:     volume.Name,
+			Mount:    mount,
+			ReadOnly: volume.ReadOnly,
+		})
+	}
+
+	return storage, params, nil
+}
+
+func autoscaleToManifestAutoscale(policy *types.AutoscalePolicy) (*manifest.ServiceAutoscale, error) {
+	if policy.MinReplicas < 1 {
+		return nil, fmt.Errorf("autoscale min replicas must be at least 1")
+	}
+	if policy.MaxReplicas < policy.MinReplicas {
+		return nil, fmt.Errorf("autoscale max replicas %d is lower than the min replicas %d", policy.MaxReplicas, policy.MinReplicas)
+	}
+	if policy.MaxReplicas > types.MaxReplicas {
+		return nil, fmt.Errorf("autoscale max replicas can not exceed %d", types.MaxReplicas)
+	}
+	if policy.TargetCPUUtilization < 0 || policy.TargetMemoryUtilization < 0 {
+		return nil, fmt.Errorf("autoscale target utilisation can not be negative")
+	}
+	if policy.TargetCPUUtilization == 0 && policy.TargetMemoryUtilization == 0 {
+		return nil, fmt.Errorf("autoscale needs a target cpu or memory utilisation")
+	}
+
+	return &manifest.ServiceAutoscale{
+		MinReplicas:       int32(policy.MinReplicas),
+		MaxReplicas:       int32(policy.MaxReplicas),
+		CPUUtilization:    int32(policy.TargetCPUUtilization),
+		MemoryUtilization: int32(policy.TargetMemoryUtilization),
+	}, nil
+}
+
+func probeToManifestProbe(probe *types.Probe) (*manifest.ServiceProbe, error) {
+	handlers := 0
+	s := &manifest.ServiceProbe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+
+	if probe.HTTPGet != nil {
+		handlers++
+		if probe.HTTPGet.Port <= 0 || probe.HTTPGet.Port > 65535 {
+			return nil, fmt.Errorf("invalid port %d", probe.HTTPGet.Port)
+		}
+
+		scheme := strings.ToUpper(probe.HTTPGet.Scheme)
+		if scheme != "" && scheme != "HTTP" && scheme != "HTTPS" {
+			return nil, fmt.Errorf("invalid scheme %s", probe.HTTPGet.Scheme)
+		}
+
+		path := probe.HTTPGet.Path
+		if path == "" {
+			path = "/"
+		}
+
+		s.Type = manifest.ProbeHTTPGet
+		s.Path = path
+		s.Scheme = scheme
+		s.Port = uint32(probe.HTTPGet.Port)
+	}
+
+	if probe.TCPSocket != nil {
+		handlers++
+		if probe.TCPSocket.Port <= 0 || probe.TCPSocket.Port > 65535 {
+			return nil, fmt.Errorf("invalid port %d", probe.TCPSocket.Port)
+		}
+
+		s.Type = manifest.ProbeTCPSocket
+		s.Port = uint32(probe.TCPSocket.Port)
+	}
+
+	if probe.Exec != nil {
+		handlers++
+		if len(probe.Exec.Command) == 0 {
+			return nil, fmt.Errorf("exec command can not be empty")
+		}
+
+		s.Type = manifest.ProbeExec
+		s.Command = probe.Exec.Command
+	}
+
+	if handlers != 1 {
+		return nil, fmt.Errorf("exactly one of HTTPGet, TCPSocket or Exec must be set")
+	}
+
+	if probe.InitialDelaySeconds < 0 || probe.PeriodSeconds < 0 || probe.TimeoutSeconds < 0 || probe.SuccessThreshold < 0 || probe.FailureThreshold < 0 {
+		return nil, fmt.Errorf("timing parameters can not be negative")
+	}
+
 	return s, nil
 }
 
@@ -92,6 +359,68 @@ func envToManifestEnv(serviceEnv types.Env) []string {
 	return envs
 }
 
+// filesToManifestFiles checks the files mounted in the container, they are kept in a
+// single config map
+func filesToManifestFiles(files types.Files) ([]manifest.ServiceFile, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	manifestFiles := make([]manifest.ServiceFile, 0, len(files))
+	paths := make(map[string]struct{})
+	size := 0
+	for _, file := range files {
+		filePath := path.Clean(file.Path)
+		if !path.IsAbs(filePath) || filePath == "/" {
+			return nil, fmt.Errorf("file path %s must be an absolute path other than /", file.Path)
+		}
+		if _, ok := paths[filePath]; ok {
+			return nil, fmt.Errorf("duplicate file %s", filePath)
+		}
+		paths[filePath] = struct{}{}
+
+		mode := file.Mode
+		if mode == 0 {
+			mode = defaultFileMode
+		}
+		if mode < 0 || mode > 0777 {
+			return nil, fmt.Errorf("file %s mode %o must be between 0 and 0777", filePath, mode)
+		}
+
+		size += len(file.Content)
+		manifestFiles = append(manifestFiles, manifest.ServiceFile{
+			Path:    filePath,
+			Content: file.Content,
+			Mode:    mode,
+		})
+	}
+
+	if size > corev1.MaxSecretSize {
+		return nil, fmt.Errorf("files of %d bytes exceed the limit of %d bytes", size, corev1.MaxSecretSize)
+	}
+
+	return manifestFiles, nil
+}
+
+// secretEnvToManifestSecretEnv checks the names of the secret env, which can not be set in the plain env too
+func secretEnvToManifestSecretEnv(secretEnv types.Env, env types.Env) (map[string]string, error) {
+	if len(secretEnv) == 0 {
+		return nil, nil
+	}
+
+	manifestSecretEnv := make(map[string]string, len(secretEnv))
+	for key, value := range secretEnv {
+		if errs := validation.IsEnvVarName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid secret env name %s: %s", key, strings.Join(errs, ", "))
+		}
+		if _, ok := env[key]; ok {
+			return nil, fmt.Errorf("env %s is both plain and secret", key)
+		}
+		manifestSecretEnv[key] = value
+	}
+	return manifestSecretEnv, nil
+}
+
 func imageToServiceName(image string) string {
 	names := strings.Split(image, "/")
 	names = strings.Split(names[len(names)-1], ":")
@@ -132,11 +461,85 @@ func exposesFromPorts(ports types.Ports) ([]*manifest.ServiceExpose, error) {
 			return nil, err
 		}
 		serviceExpose := &manifest.ServiceExpose{Port: uint32(port.Port), ExternalPort: uint32(port.Port), Proto: proto, Global: true}
+
+		for _, host := range port.Hosts {
+			if errs := validation.IsDNS1123Subdomain(host); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid host %s: %s", host, strings.Join(errs, ","))
+			}
+		}
+		serviceExpose.Hosts = port.Hosts
+		serviceExpose.HttpOptions = httpOptionsToManifestHTTPOptions(port.HTTPOptions)
+
 		serviceExposes = append(serviceExposes, serviceExpose)
 	}
 	return serviceExposes, nil
 }
 
+// checkHosts rejects the hosts a deployment does not own: the hosts under the ingress domain
+// of the provider other than the ones generated for its services, and the hosts routed by
+// the ingresses of the other deployments
+func checkHosts(settings builder.Settings, ns string, group *manifest.Group, ingresses *netv1.IngressList) error {
+	owners := make(map[string]string)
+	for _, ingress := range ingresses.Items {
+		for _, rule := range ingress.Spec.Rules {
+			owners[rule.Host] = ingress.Namespace
+		}
+	}
+
+	for _, service := range group.Services {
+		generated := builder.GeneratedHost(settings, ns, service.Name, len(group.Services))
+		for _, expose := range service.Expose {
+			for _, host := range expose.Hosts {
+				domain := settings.DeploymentIngressDomain
+				if domain != "" && host != generated && (host == domain || strings.HasSuffix(host, "."+domain)) {
+					return fmt.Errorf("service %s: host %s is reserved by the provider", service.Name, host)
+				}
+
+				if owner, ok := owners[host]; ok && owner != ns {
+					return fmt.Errorf("service %s: host %s is used by another deployment", service.Name, host)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+var defaultHTTPOptions = manifest.ServiceExposeHTTPOptions{
+	MaxBodySize: 1048576,
+	ReadTimeout: 60000,
+	SendTimeout: 60000,
+	NextTries:   3,
+	NextTimeout: 0,
+	NextCases:   []string{"error", "timeout"},
+}
+
+// httpOptionsToManifestHTTPOptions fills the unset options with their defaults
+func httpOptionsToManifestHTTPOptions(options *types.HTTPOptions) manifest.ServiceExposeHTTPOptions {
+	result := defaultHTTPOptions
+	if options == nil {
+		return result
+	}
+
+	if options.MaxBodySize > 0 {
+		result.MaxBodySize = options.MaxBodySize
+	}
+	if options.ReadTimeout > 0 {
+		result.ReadTimeout = options.ReadTimeout
+	}
+	if options.SendTimeout > 0 {
+		result.SendTimeout = options.SendTimeout
+	}
+	if options.NextTries > 0 {
+		result.NextTries = options.NextTries
+	}
+	result.NextTimeout = options.NextTimeout
+	if len(options.NextCases) > 0 {
+		result.NextCases = options.NextCases
+	}
+
+	return result
+}
+
 func k8sDeploymentsToServices(deploymentList *appsv1.DeploymentList) ([]*types.Service, error) {
 	services := make([]*types.Service, 0, len(deploymentList.Items))
 
@@ -156,11 +559,7 @@ func k8sDeploymentToService(deployment *appsv1.Deployment) (*types.Service, erro
 		return nil, fmt.Errorf("deployment container can not empty")
 	}
 
-	container := deployment.Spec.Template.Spec.Containers[0]
-	service := &types.Service{Image: container.Image, Name: container.Name}
-	service.CPU = container.Resources.Limits.Cpu().AsApproximateFloat64()
-	service.Memory = container.Resources.Limits.Memory().Value() / 1000000
-	service.Storage = int64(container.Resources.Limits.StorageEphemeral().AsApproximateFloat64()) / 1000000
+	service := k8sContainerToService(&deployment.Spec.Template.Spec.Containers[0], deployment.Spec.Replicas)
 
 	status := types.ReplicasStatus{
 		TotalReplicas:     int(deployment.Status.Replicas),
@@ -172,6 +571,107 @@ func k8sDeploymentToService(deployment *appsv1.Deployment) (*types.Service, erro
 	return service, nil
 }
 
+func k8sStatefulSetsToServices(statefulSetList *appsv1.StatefulSetList) ([]*types.Service, error) {
+	services := make([]*types.Service, 0, len(statefulSetList.Items))
+
+	for _, statefulSet := range statefulSetList.Items {
+		s, err := k8sStatefulSetToService(&statefulSet)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+
+	return services, nil
+}
+
+func k8sStatefulSetToService(statefulSet *appsv1.StatefulSet) (*types.Service, error) {
+	if len(statefulSet.Spec.Template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("statefulset container can not empty")
+	}
+
+	container := &statefulSet.Spec.Template.Spec.Containers[0]
+	service := k8sContainerToService(container, statefulSet.Spec.Replicas)
+
+	mounts := make(map[string]corev1.VolumeMount)
+	for _, mount := range container.VolumeMounts {
+		mounts[mount.Name] = mount
+	}
+
+	for _, pvc := range statefulSet.Spec.VolumeClaimTemplates {
+		mount, ok := mounts[pvc.Name]
+		if !ok {
+			continue
+		}
+
+		volume := types.Volume{
+			Name:     strings.TrimPrefix(pvc.Name, container.Name+"-"),
+			Size:     pvc.Spec.Resources.Requests.Storage().Value() / 1000000,
+			Mount:    mount.MountPath,
+			ReadOnly: mount.ReadOnly,
+		}
+		if pvc.Spec.StorageClassName != nil {
+			volume.StorageClass = *pvc.Spec.StorageClassName
+		}
+		service.Volumes = append(service.Volumes, volume)
+	}
+
+	status := types.ReplicasStatus{
+		TotalReplicas:     int(statefulSet.Status.Replicas),
+		ReadyReplicas:     int(statefulSet.Status.ReadyReplicas),
+		AvailableReplicas: int(statefulSet.Status.AvailableReplicas),
+	}
+	service.Status = status
+
+	return service, nil
+}
+
+func k8sContainerToService(container *corev1.Container, replicas *int32) *types.Service {
+	service := &types.Service{Image: container.Image, Name: container.Name, Replicas: defaultReplicas}
+	if replicas != nil {
+		service.Replicas = int(*replicas)
+	}
+	service.LivenessProbe = k8sProbeToProbe(container.LivenessProbe)
+	service.ReadinessProbe = k8sProbeToProbe(container.ReadinessProbe)
+	service.StartupProbe = k8sProbeToProbe(container.StartupProbe)
+	service.Command = container.Command
+	service.Arguments = container.Args
+	service.CPU = container.Resources.Limits.Cpu().AsApproximateFloat64()
+	service.Memory = container.Resources.Limits.Memory().Value() / 1000000
+	service.Storage = int64(container.Resources.Limits.StorageEphemeral().AsApproximateFloat64()) / 1000000
+
+	return service
+}
+
+func k8sProbeToProbe(kprobe *corev1.Probe) *types.Probe {
+	if kprobe == nil {
+		return nil
+	}
+
+	probe := &types.Probe{
+		InitialDelaySeconds: kprobe.InitialDelaySeconds,
+		PeriodSeconds:       kprobe.PeriodSeconds,
+		TimeoutSeconds:      kprobe.TimeoutSeconds,
+		SuccessThreshold:    kprobe.SuccessThreshold,
+		FailureThreshold:    kprobe.FailureThreshold,
+	}
+
+	switch {
+	case kprobe.HTTPGet != nil:
+		probe.HTTPGet = &types.HTTPGetProbe{
+			Path:   kprobe.HTTPGet.Path,
+			Port:   kprobe.HTTPGet.Port.IntValue(),
+			Scheme: string(kprobe.HTTPGet.Scheme),
+		}
+	case kprobe.TCPSocket != nil:
+		probe.TCPSocket = &types.TCPSocketProbe{Port: kprobe.TCPSocket.Port.IntValue()}
+	case kprobe.Exec != nil:
+		probe.Exec = &types.ExecProbe{Command: kprobe.Exec.Command}
+	}
+
+	return probe
+}
+
 func k8sServiceToPortMap(serviceList *corev1.ServiceList) (map[string]types.Ports, error) {
 	portMap := make(map[string]types.Ports)
 	for _, service := range serviceList.Items {
@@ -183,6 +683,53 @@ func k8sServiceToPortMap(serviceList *corev1.ServiceList) (map[string]types.Port
 	return portMap, nil
 }
 
+// certificateStatus reads the certificate of the tls secret, supplied with the service
+// or issued by cert-manager
+func certificateStatus(secret *corev1.Secret, now time.Time) *types.CertificateStatus {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil {
+		return &types.CertificateStatus{Message: "certificate not issued yet"}
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return &types.CertificateStatus{Message: fmt.Sprintf("invalid certificate: %s", err.Error())}
+	}
+
+	status := &types.CertificateStatus{Hosts: cert.DNSNames, NotAfter: cert.NotAfter, Ready: true}
+	if now.After(cert.NotAfter) {
+		status.Ready = false
+		status.Message = "certificate expired"
+	} else if now.Before(cert.NotBefore) {
+		status.Ready = false
+		status.Message = "certificate not valid yet"
+	}
+	return status
+}
+
+// k8sIngressToURIMap returns the hosts of the ingresses, keyed by service name.
+// The hosts of their load balancers are included when exposeLBHosts is set.
+func k8sIngressToURIMap(ingressList *netv1.IngressList, exposeLBHosts bool) map[string][]string {
+	uriMap := make(map[string][]string)
+	for _, ingress := range ingressList.Items {
+		for _, rule := range ingress.Spec.Rules {
+			uriMap[ingress.Name] = append(uriMap[ingress.Name], rule.Host)
+		}
+
+		if !exposeLBHosts {
+			continue
+		}
+		for _, lb := range ingress.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				uriMap[ingress.Name] = append(uriMap[ingress.Name], lb.Hostname)
+			} else if lb.IP != "" {
+				uriMap[ingress.Name] = append(uriMap[ingress.Name], lb.IP)
+			}
+		}
+	}
+	return uriMap
+}
+
 func servicePortsToPortPairs(servicePorts []corev1.ServicePort) types.Ports {
 	ports := make([]types.Port, 0, len(servicePorts))
 	for _, servicePort := range servicePorts {
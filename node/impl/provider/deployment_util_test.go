@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/stretchr/testify/require"
+)
+
+// the hosts of the ports are stored with the services, the ingress rebuilt from the stored
+// deployment on rollback or repair routes them
+func TestStoredHostsIngress(t *testing.T) {
+	ports := types.Ports{{Protocol: types.TCP, Port: 80, ExposePort: 30080, Hosts: []string{"example.com"}}}
+	value, err := ports.Value()
+	require.NoError(t, err)
+
+	var stored types.Ports
+	require.NoError(t, stored.Scan(value))
+
+	deployment := &types.Deployment{
+		ID:    "id",
+		Owner: "owner",
+		Services: []*types.Service{{
+			Name:             "web",
+			Image:            "nginx",
+			Ports:            stored,
+			TLS:              &types.TLSConfig{},
+			ComputeResources: types.ComputeResources{CPU: 1},
+		}},
+	}
+
+	clusterDeployment, err := ClusterDeploymentFromDeployment(deployment)
+	require.NoError(t, err)
+
+	ingress, err := builder.BuildIngress(builder.NewWorkload(builder.NewDefaultSettings(), clusterDeployment, 0)).Create()
+	require.NoError(t, err)
+
+	require.Len(t, ingress.Spec.Rules, 1)
+	require.Equal(t, "example.com", ingress.Spec.Rules[0].Host)
+	require.Len(t, ingress.Spec.TLS, 1)
+	require.Equal(t, []string{"example.com"}, ingress.Spec.TLS[0].Hosts)
+}
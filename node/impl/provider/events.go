@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const eventsBufferSize = 32
+
+// WatchEvents streams the events of the deployment namespace, starting with the events already recorded.
+// The channel is closed when the context is done or the watch fails.
+func (m *manager) WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	if _, err := m.kc.GetNS(ctx, ns); err != nil {
+		return nil, err
+	}
+
+	w, err := m.kc.WatchEvents(ctx, ns, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan types.ServiceEvent, eventsBufferSize)
+	go func() {
+		defer close(out)
+		defer func() { w.Stop() }()
+
+		podServices := make(map[string]string)
+		resourceVersion := ""
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case result, ok := <-w.ResultChan():
+				if !ok {
+					// the api server ends watches after a while, resume after the last received event
+					w.Stop()
+
+					var err error
+					w, err = m.kc.WatchEvents(ctx, ns, metav1.ListOptions{ResourceVersion: resourceVersion})
+					if err != nil {
+						log.Errorf("watch events of %s: %v", ns, err)
+						return
+					}
+					continue
+				}
+
+				if result.Type == watch.Error {
+					log.Errorf("watch events of %s: %v", ns, result.Object)
+					return
+				}
+
+				event, ok := result.Object.(*corev1.Event)
+				if !ok {
+					continue
+				}
+				resourceVersion = event.ResourceVersion
+
+				if result.Type == watch.Deleted {
+					continue
+				}
+
+				serviceEvent := types.ServiceEvent{
+					ServiceName: m.eventServiceName(ctx, ns, event, podServices),
+					Events:      []types.Event{types.Event(event.Message)},
+				}
+
+				select {
+				case out <- serviceEvent:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// eventServiceName returns the name of the service the object of the event belongs to
+func (m *manager) eventServiceName(ctx context.Context, ns string, event *corev1.Event, podServices map[string]string) string {
+	object := event.InvolvedObject
+	switch object.Kind {
+	case "Pod":
+		if name, ok := podServices[object.Name]; ok {
+			return name
+		}
+
+		pod, err := m.kc.GetPod(ctx, ns, object.Name)
+		if err != nil {
+			return object.Name
+		}
+
+		name := pod.Labels[builder.TitanManifestServiceLabelName]
+		if name == "" {
+			name = object.Name
+		}
+		podServices[object.Name] = name
+		return name
+	case "ReplicaSet":
+		// replica sets are named after their deployment, followed by the pod template hash
+		if i := strings.LastIndex(object.Name, "-"); i > 0 {
+			return object.Name[:i]
+		}
+		return object.Name
+	default:
+		return object.Name
+	}
+}
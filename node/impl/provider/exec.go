@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/lib/execstream"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+const execBufferSize = 64
+
+// ExecDeployment runs a command in a running pod of a service of the deployment.
+// The stdin is an execstream of the input and the terminal resizes of the caller.
+// The output is streamed until the command exits, the last message carrying its exit code.
+func (m *manager) ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error) {
+	if len(opts.Command) == 0 {
+		return nil, fmt.Errorf("command can not be empty")
+	}
+
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	podName, err := m.execPod(ctx, ns, opts.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	// without stdin its frames are discarded, the resizes are still decoded
+	input, sizes := execstream.Demux(stdin)
+	if !opts.Stdin {
+		input.Close()
+	}
+
+	podExecOptions := &corev1.PodExecOptions{
+		Command: opts.Command,
+		Stdin:   opts.Stdin,
+		Stdout:  true,
+		Stderr:  !opts.TTY,
+		TTY:     opts.TTY,
+	}
+
+	out := make(chan types.ExecOutput, execBufferSize)
+
+	streams := remotecommand.StreamOptions{
+		Stdout: &execWriter{ctx: ctx, out: out},
+		Tty:    opts.TTY,
+	}
+	if opts.Stdin {
+		streams.Stdin = input
+	}
+	if opts.TTY {
+		streams.TerminalSizeQueue = &terminalSizeQueue{ctx: ctx, sizes: sizes}
+	} else {
+		streams.Stderr = &execWriter{ctx: ctx, out: out, stderr: true}
+	}
+
+	go func() {
+		defer close(out)
+		defer input.Close()
+		if closer, ok := stdin.(io.Closer); ok {
+			defer closer.Close()
+		}
+
+		err := m.kc.Exec(ctx, ns, podName, podExecOptions, streams)
+
+		result := types.ExecOutput{Exited: true}
+		var exitErr exec.CodeExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitStatus()
+		} else if err != nil {
+			log.Errorf("exec in pod %s: %v", podName, err)
+			result.ExitCode = 1
+			result.Error = err.Error()
+		}
+
+		select {
+		case out <- result:
+		case <-ctx.Done():
+		}
+	}()
+
+	return out, nil
+}
+
+// execPod returns a running pod of the service
+func (m *manager) execPod(ctx context.Context, ns string, service string) (string, error) {
+	pods, err := m.getPods(ctx, ns)
+	if err != nil {
+		return "", err
+	}
+
+	services := make(map[string]struct{})
+	for _, serviceName := range pods {
+		services[serviceName] = struct{}{}
+	}
+
+	if service == "" {
+		if len(services) != 1 {
+			return "", fmt.Errorf("the deployment has %d services, one must be specified", len(services))
+		}
+		for serviceName := range services {
+			service = serviceName
+		}
+	}
+
+	var podNames []string
+	for podName, serviceName := range pods {
+		if serviceName == service {
+			podNames = append(podNames, podName)
+		}
+	}
+	sort.Strings(podNames)
+
+	for _, podName := range podNames {
+		pod, err := m.kc.GetPod(ctx, ns, podName)
+		if err != nil {
+			return "", err
+		}
+
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return podName, nil
+		}
+	}
+
+	return "", fmt.Errorf("service %s has no running pod", service)
+}
+
+// execWriter sends the output of the command on the channel
+type execWriter struct {
+	ctx    context.Context
+	out    chan<- types.ExecOutput
+	stderr bool
+}
+
+func (w *execWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	output := types.ExecOutput{Stdout: data}
+	if w.stderr {
+		output = types.ExecOutput{Stderr: data}
+	}
+
+	select {
+	case w.out <- output:
+		return len(p), nil
+	case <-w.ctx.Done():
+		return 0, w.ctx.Err()
+	}
+}
+
+// terminalSizeQueue feeds the terminal resizes of the caller to the executor
+type terminalSizeQueue struct {
+	ctx   context.Context
+	sizes <-chan execstream.TerminalSize
+}
+
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size, ok := <-q.sizes:
+		if !ok {
+			return nil
+		}
+		return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+	case <-q.ctx.Done():
+		return nil
+	}
+}
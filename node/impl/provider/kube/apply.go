@@ -4,8 +4,10 @@ package kube
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -78,6 +80,15 @@ func applyDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Dep
 	return err
 }
 
+// deleteDeployment removes the deployment of a service which runs as a stateful set now
+func deleteDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Deployment) error {
+	err := kc.AppsV1().Deployments(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
 func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.StatefulSet) error {
 	obj, err := kc.AppsV1().StatefulSets(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
 
@@ -115,3 +126,127 @@ func applyService(ctx context.Context, kc kubernetes.Interface, b builder.Servic
 	}
 	return err
 }
+
+func applyHorizontalPodAutoscaler(ctx context.Context, kc kubernetes.Interface, b builder.HorizontalPodAutoscaler) error {
+	obj, err := kc.AutoscalingV2().HorizontalPodAutoscalers(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			_, err = kc.AutoscalingV2().HorizontalPodAutoscalers(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+		}
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			_, err = kc.AutoscalingV2().HorizontalPodAutoscalers(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+		}
+	}
+	return err
+}
+
+// deleteHorizontalPodAutoscaler removes the autoscaler of a service which is not autoscaled anymore
+func deleteHorizontalPodAutoscaler(ctx context.Context, kc kubernetes.Interface, b builder.HorizontalPodAutoscaler) error {
+	err := kc.AutoscalingV2().HorizontalPodAutoscalers(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func applyIngress(ctx context.Context, kc kubernetes.Interface, b builder.Ingress) error {
+	obj, err := kc.NetworkingV1().Ingresses(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			_, err = kc.NetworkingV1().Ingresses(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+		}
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			_, err = kc.NetworkingV1().Ingresses(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+		}
+	}
+	return err
+}
+
+// deleteIngress removes the ingress of a service which has no http host anymore
+func deleteIngress(ctx context.Context, kc kubernetes.Interface, b builder.Ingress) error {
+	err := kc.NetworkingV1().Ingresses(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// applySecret creates or updates the secret, it returns the applied secret
+func applySecret(ctx context.Context, kc kubernetes.Interface, b builder.Secret) (*corev1.Secret, error) {
+	obj, err := kc.CoreV1().Secrets(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			obj, err = kc.CoreV1().Secrets(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+		}
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			obj, err = kc.CoreV1().Secrets(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+		}
+	}
+	return obj, err
+}
+
+// deleteSecret removes a secret the service does not use anymore
+func deleteSecret(ctx context.Context, kc kubernetes.Interface, b builder.Secret) error {
+	err := kc.CoreV1().Secrets(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func applyConfigMap(ctx context.Context, kc kubernetes.Interface, b builder.ConfigMap) error {
+	obj, err := kc.CoreV1().ConfigMaps(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+
+	switch {
+	case err == nil:
+		obj, err = b.Update(obj)
+		if err == nil {
+			_, err = kc.CoreV1().ConfigMaps(b.NS()).Update(ctx, obj, metav1.UpdateOptions{})
+		}
+	case errors.IsNotFound(err):
+		obj, err = b.Create()
+		if err == nil {
+			_, err = kc.CoreV1().ConfigMaps(b.NS()).Create(ctx, obj, metav1.CreateOptions{})
+		}
+	}
+	return err
+}
+
+// deleteConfigMap removes the config map of a service which has no files anymore
+func deleteConfigMap(ctx context.Context, kc kubernetes.Interface, b builder.ConfigMap) error {
+	err := kc.CoreV1().ConfigMaps(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteStatefulSet removes the stateful set of a service which has no volumes anymore,
+// and the claims of its volumes
+func deleteStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.StatefulSet) error {
+	err := kc.AppsV1().StatefulSets(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	selector := fmt.Sprintf("%s=%s", builder.TitanManifestServiceLabelName, b.Name())
+	return kc.CoreV1().PersistentVolumeClaims(b.NS()).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector})
+}
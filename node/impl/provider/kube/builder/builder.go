@@ -10,6 +10,9 @@ const (
 	TitanManagedLabelName         = "titan.provider"
 	TitanManifestServiceLabelName = "titan.provider/manifest-service"
 
+	// TitanRestartedAtAnnotationName is changed in the pod templates to restart the pods of a service
+	TitanRestartedAtAnnotationName = "titan.provider/restartedAt"
+
 	// AkashNetworkStorageClasses    = "akash.network/storageclasses"
 	// AkashServiceTarget            = "akash.network/service-target"
 	// AkashServiceCapabilityGPU     = "akash.network/capabilities.gpu"
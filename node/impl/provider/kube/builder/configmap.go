@@ -0,0 +1,141 @@
+package builder
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TitanFilesHashAnnotationName holds the hash of the files in the pod templates, the pods
+// of a service are restarted when its files change
+const TitanFilesHashAnnotationName = "titan.provider/files-hash"
+
+// FilesVolumeName is reserved for the volume of the files, the persistent volumes of a
+// service are named after it too and can not use it
+const FilesVolumeName = "files"
+
+// ConfigMapName returns the name of the config map holding the files of the service
+func ConfigMapName(serviceName string) string {
+	return fmt.Sprintf("%s-%s", serviceName, FilesVolumeName)
+}
+
+// configMapKey is the key of a file in the config map, the paths can not be keys
+func configMapKey(idx int) string {
+	return fmt.Sprintf("file-%d", idx)
+}
+
+type ConfigMap interface {
+	workloadBase
+	Create() (*corev1.ConfigMap, error)
+	Update(obj *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	Any() bool
+}
+
+type configMap struct {
+	Workload
+}
+
+var _ ConfigMap = (*configMap)(nil)
+
+// BuildConfigMap stores the files of the service, they are mounted in its container
+func BuildConfigMap(workload Workload) ConfigMap {
+	return &configMap{Workload: workload}
+}
+
+func (b *configMap) Name() string {
+	return ConfigMapName(b.Workload.Name())
+}
+
+// Any returns whether the service has files
+func (b *configMap) Any() bool {
+	return len(b.deployment.ManifestGroup().Services[b.serviceIdx].Files) > 0
+}
+
+func (b *configMap) Create() (*corev1.ConfigMap, error) { // nolint:golint,unparam
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Data: b.data(),
+	}, nil
+}
+
+func (b *configMap) Update(obj *corev1.ConfigMap) (*corev1.ConfigMap, error) { // nolint:golint,unparam
+	obj.Labels = b.labels()
+	obj.Data = b.data()
+	obj.BinaryData = nil
+	return obj, nil
+}
+
+func (b *configMap) data() map[string]string {
+	files := b.deployment.ManifestGroup().Services[b.serviceIdx].Files
+
+	data := make(map[string]string, len(files))
+	for i, file := range files {
+		data[configMapKey(i)] = file.Content
+	}
+	return data
+}
+
+// filesVolumes returns the volume of the config map of the service, none when it has no files
+func (b *Workload) filesVolumes() []corev1.Volume {
+	files := b.deployment.ManifestGroup().Services[b.serviceIdx].Files
+	if len(files) == 0 {
+		return nil
+	}
+
+	items := make([]corev1.KeyToPath, 0, len(files))
+	for i, file := range files {
+		mode := file.Mode
+		items = append(items, corev1.KeyToPath{
+			Key:  configMapKey(i),
+			Path: configMapKey(i),
+			Mode: &mode,
+		})
+	}
+
+	return []corev1.Volume{{
+		Name: ConfigMapName(b.Name()),
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: ConfigMapName(b.Name())},
+				Items:                items,
+			},
+		},
+	}}
+}
+
+// filesVolumeMounts mounts every file of the service at its path. The files mounted
+// with a sub path are not refreshed, the pods are restarted instead.
+func (b *Workload) filesVolumeMounts() []corev1.VolumeMount {
+	files := b.deployment.ManifestGroup().Services[b.serviceIdx].Files
+
+	mounts := make([]corev1.VolumeMount, 0, len(files))
+	for i, file := range files {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      ConfigMapName(b.Name()),
+			MountPath: file.Path,
+			SubPath:   configMapKey(i),
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// filesHash returns the hash of the files of the service, empty when it has none
+func (b *Workload) filesHash() string {
+	files := b.deployment.ManifestGroup().Services[b.serviceIdx].Files
+	if len(files) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	for _, file := range files {
+		fmt.Fprintf(h, "%s\x00%o\x00%d\x00%s", file.Path, file.Mode, len(file.Content), file.Content)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
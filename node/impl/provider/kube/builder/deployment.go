@@ -35,11 +35,14 @@ func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unp
 			Replicas: b.replicas(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: b.labels(),
+					Labels:      b.labels(),
+					Annotations: b.podAnnotations(nil),
 				},
 				Spec: corev1.PodSpec{
 					Containers:       []corev1.Container{b.container()},
 					ImagePullSecrets: b.imagePullSecrets(),
+					RuntimeClassName: b.runtimeClassName(),
+					Volumes:          b.filesVolumes(),
 				},
 			},
 		},
@@ -51,10 +54,16 @@ func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unp
 func (b *deployment) Update(obj *appsv1.Deployment) (*appsv1.Deployment, error) { // nolint:golint,unparam
 	obj.Labels = b.labels()
 	obj.Spec.Selector.MatchLabels = b.labels()
-	obj.Spec.Replicas = b.replicas()
+	if !b.autoscaled() {
+		// the replicas of autoscaled services belong to their autoscaler
+		obj.Spec.Replicas = b.replicas()
+	}
 	obj.Spec.Template.Labels = b.labels()
+	obj.Spec.Template.Annotations = b.podAnnotations(obj.Spec.Template.Annotations)
 	obj.Spec.Template.Spec.Containers = []corev1.Container{b.container()}
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
+	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClassName()
+	obj.Spec.Template.Spec.Volumes = b.filesVolumes()
 
 	return obj, nil
 }
@@ -0,0 +1,98 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TitanSecretEnvVersionAnnotationName holds the resource version of the env secret in the pod templates,
+// the pods of a service are restarted when its secret env changes. Unlike a hash of the values, the
+// version tells nothing about them to the readers of the pods.
+const TitanSecretEnvVersionAnnotationName = "titan.provider/secret-env-version"
+
+// EnvSecretName returns the name of the secret holding the secret env of the service
+func EnvSecretName(serviceName string) string {
+	return fmt.Sprintf("%s-env", serviceName)
+}
+
+type envSecret struct {
+	Workload
+}
+
+var _ Secret = (*envSecret)(nil)
+
+// BuildEnvSecret stores the secret env of the service, the containers of the service
+// reference its keys
+func BuildEnvSecret(workload Workload) Secret {
+	return &envSecret{Workload: workload}
+}
+
+func (b *envSecret) Name() string {
+	return EnvSecretName(b.Workload.Name())
+}
+
+// Any returns whether the service has secret env
+func (b *envSecret) Any() bool {
+	return len(b.deployment.ManifestGroup().Services[b.serviceIdx].SecretEnv) > 0
+}
+
+func (b *envSecret) Create() (*corev1.Secret, error) { // nolint:golint,unparam
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: b.data(),
+	}, nil
+}
+
+func (b *envSecret) Update(obj *corev1.Secret) (*corev1.Secret, error) { // nolint:golint,unparam
+	obj.Labels = b.labels()
+	obj.Type = corev1.SecretTypeOpaque
+	obj.Data = b.data()
+	return obj, nil
+}
+
+func (b *envSecret) data() map[string][]byte {
+	env := b.deployment.ManifestGroup().Services[b.serviceIdx].SecretEnv
+
+	data := make(map[string][]byte, len(env))
+	for key, value := range env {
+		data[key] = []byte(value)
+	}
+	return data
+}
+
+// secretEnvVars references the keys of the secret env of the service
+func (b *Workload) secretEnvVars() []corev1.EnvVar {
+	env := b.deployment.ManifestGroup().Services[b.serviceIdx].SecretEnv
+
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	vars := make([]corev1.EnvVar, 0, len(keys))
+	for _, key := range keys {
+		vars = append(vars, corev1.EnvVar{
+			Name: key,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: EnvSecretName(b.Name())},
+					Key:                  key,
+				},
+			},
+		})
+	}
+	return vars
+}
+
+// SetSecretEnvVersion sets the resource version of the applied env secret, it is empty when the service has no secret env
+func (b *Workload) SetSecretEnvVersion(version string) {
+	b.secretEnvVersion = version
+}
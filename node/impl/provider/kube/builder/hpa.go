@@ -0,0 +1,107 @@
+package builder
+
+import (
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ScaleTargetDeployment  = "Deployment"
+	ScaleTargetStatefulSet = "StatefulSet"
+)
+
+type HorizontalPodAutoscaler interface {
+	workloadBase
+	Create() (*autoscalingv2.HorizontalPodAutoscaler, error)
+	Update(obj *autoscalingv2.HorizontalPodAutoscaler) (*autoscalingv2.HorizontalPodAutoscaler, error)
+	Any() bool
+}
+
+type horizontalPodAutoscaler struct {
+	Workload
+	targetKind string
+}
+
+var _ HorizontalPodAutoscaler = (*horizontalPodAutoscaler)(nil)
+
+// BuildHorizontalPodAutoscaler scales the workload of the service, a deployment or a stateful set
+func BuildHorizontalPodAutoscaler(workload Workload, targetKind string) HorizontalPodAutoscaler {
+	return &horizontalPodAutoscaler{
+		Workload:   workload,
+		targetKind: targetKind,
+	}
+}
+
+// Any returns whether the service is autoscaled
+func (b *horizontalPodAutoscaler) Any() bool {
+	return b.deployment.ManifestGroup().Services[b.serviceIdx].Autoscale != nil
+}
+
+func (b *horizontalPodAutoscaler) Create() (*autoscalingv2.HorizontalPodAutoscaler, error) { // nolint:golint,unparam
+	spec, err := b.spec()
+	if err != nil {
+		return nil, err
+	}
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Spec: spec,
+	}, nil
+}
+
+func (b *horizontalPodAutoscaler) Update(obj *autoscalingv2.HorizontalPodAutoscaler) (*autoscalingv2.HorizontalPodAutoscaler, error) { // nolint:golint,unparam
+	spec, err := b.spec()
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Labels = b.labels()
+	obj.Spec = spec
+	return obj, nil
+}
+
+func (b *horizontalPodAutoscaler) spec() (autoscalingv2.HorizontalPodAutoscalerSpec, error) {
+	autoscale := b.deployment.ManifestGroup().Services[b.serviceIdx].Autoscale
+	if autoscale == nil {
+		return autoscalingv2.HorizontalPodAutoscalerSpec{}, fmt.Errorf("service %s is not autoscaled", b.Name())
+	}
+
+	minReplicas := autoscale.MinReplicas
+	spec := autoscalingv2.HorizontalPodAutoscalerSpec{
+		ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+			APIVersion: "apps/v1",
+			Kind:       b.targetKind,
+			Name:       b.Name(),
+		},
+		MinReplicas: &minReplicas,
+		MaxReplicas: autoscale.MaxReplicas,
+	}
+
+	if autoscale.CPUUtilization > 0 {
+		spec.Metrics = append(spec.Metrics, resourceUtilizationMetric(corev1.ResourceCPU, autoscale.CPUUtilization))
+	}
+	if autoscale.MemoryUtilization > 0 {
+		spec.Metrics = append(spec.Metrics, resourceUtilizationMetric(corev1.ResourceMemory, autoscale.MemoryUtilization))
+	}
+
+	return spec, nil
+}
+
+func resourceUtilizationMetric(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: name,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: &utilization,
+			},
+		},
+	}
+}
@@ -0,0 +1,221 @@
+package builder
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ingressClassName = "nginx"
+
+	// annotations of kubernetes/ingress-nginx
+	nginxAnnotationRoot = "nginx.ingress.kubernetes.io"
+)
+
+type Ingress interface {
+	workloadBase
+	Create() (*netv1.Ingress, error)
+	Update(obj *netv1.Ingress) (*netv1.Ingress, error)
+	Any() bool
+	Hosts() []string
+}
+
+type ingress struct {
+	Workload
+}
+
+var _ Ingress = (*ingress)(nil)
+
+// BuildIngress routes the hosts of the http exposes of the service to its cluster ip service
+func BuildIngress(workload Workload) Ingress {
+	return &ingress{Workload: workload}
+}
+
+// Any returns whether the service has an http expose with at least one host
+func (b *ingress) Any() bool {
+	return len(b.Hosts()) > 0
+}
+
+// Hosts returns the hostnames of the http exposes. When an expose has no host
+// and the provider maps deployments to its ingress domain, a host is generated.
+func (b *ingress) Hosts() []string {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	var hosts []string
+	added := make(map[string]struct{})
+	for _, expose := range service.Expose {
+		if !shouldBeIngress(expose) {
+			continue
+		}
+
+		exposeHosts := expose.Hosts
+		if len(exposeHosts) == 0 && b.settings.DeploymentIngressStaticHosts {
+			exposeHosts = []string{b.generatedHost()}
+		}
+
+		for _, host := range exposeHosts {
+			if _, ok := added[host]; ok {
+				continue
+			}
+			added[host] = struct{}{}
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
+func (b *ingress) generatedHost() string {
+	return GeneratedHost(b.settings, b.NS(), b.Workload.Name(), len(b.deployment.ManifestGroup().Services))
+}
+
+// GeneratedHost returns <deployment>.<domain>, or <service>-<deployment>.<domain> when
+// the deployment has several services
+func GeneratedHost(settings Settings, ns string, service string, services int) string {
+	if services == 1 {
+		return fmt.Sprintf("%s.%s", ns, settings.DeploymentIngressDomain)
+	}
+	return fmt.Sprintf("%s-%s.%s", service, ns, settings.DeploymentIngressDomain)
+}
+
+func (b *ingress) Create() (*netv1.Ingress, error) { // nolint:golint,unparam
+	expose, err := b.expose()
+	if err != nil {
+		return nil, err
+	}
+
+	className := ingressClassName
+	return &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        b.Name(),
+			Labels:      b.labels(),
+			Annotations: b.annotations(expose),
+		},
+		Spec: netv1.IngressSpec{
+			IngressClassName: &className,
+			Rules:            b.rules(expose),
+			TLS:              b.tls(),
+		},
+	}, nil
+}
+
+func (b *ingress) Update(obj *netv1.Ingress) (*netv1.Ingress, error) { // nolint:golint,unparam
+	expose, err := b.expose()
+	if err != nil {
+		return nil, err
+	}
+
+	className := ingressClassName
+	obj.Labels = b.labels()
+	obj.Annotations = b.annotations(expose)
+	obj.Spec.IngressClassName = &className
+	obj.Spec.Rules = b.rules(expose)
+	obj.Spec.TLS = b.tls()
+	return obj, nil
+}
+
+// Validate checks the certificate of the hosts can be provided
+func (b *ingress) Validate() error {
+	tls := b.deployment.ManifestGroup().Services[b.serviceIdx].TLS
+	if tls != nil && len(tls.Certificate) == 0 && b.settings.DeploymentIngressClusterIssuer == "" {
+		return fmt.Errorf("service %s: the provider does not issue certificates, one must be supplied", b.Workload.Name())
+	}
+	return nil
+}
+
+func (b *ingress) annotations(expose *manifest.ServiceExpose) map[string]string {
+	annotations := nginxIngressAnnotations(expose.HttpOptions)
+
+	tls := b.deployment.ManifestGroup().Services[b.serviceIdx].TLS
+	if tls == nil {
+		annotations[nginxSSLRedirectAnnotation] = "false"
+		return annotations
+	}
+
+	annotations[nginxSSLRedirectAnnotation] = "true"
+	if len(tls.Certificate) == 0 {
+		annotations[certManagerClusterIssuerAnnotation] = b.settings.DeploymentIngressClusterIssuer
+	}
+	return annotations
+}
+
+func (b *ingress) tls() []netv1.IngressTLS {
+	if b.deployment.ManifestGroup().Services[b.serviceIdx].TLS == nil {
+		return nil
+	}
+
+	return []netv1.IngressTLS{{
+		Hosts:      b.Hosts(),
+		SecretName: TLSSecretName(b.Workload.Name()),
+	}}
+}
+
+// expose returns the http expose of the service, its options apply to all the hosts
+func (b *ingress) expose() (*manifest.ServiceExpose, error) {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+	for _, expose := range service.Expose {
+		if shouldBeIngress(expose) {
+			return expose, nil
+		}
+	}
+	return nil, fmt.Errorf("service %s has no http expose", b.Name())
+}
+
+func (b *ingress) rules(expose *manifest.ServiceExpose) []netv1.IngressRule {
+	pathType := netv1.PathTypePrefix
+	backend := netv1.IngressBackend{
+		Service: &netv1.IngressServiceBackend{
+			// the cluster ip service of the workload, see BuildService
+			Name: b.Workload.Name(),
+			Port: netv1.ServiceBackendPort{Number: exposeExternalPort(expose)},
+		},
+	}
+
+	hosts := b.Hosts()
+	rules := make([]netv1.IngressRule, 0, len(hosts))
+	for _, host := range hosts {
+		rules = append(rules, netv1.IngressRule{
+			Host: host,
+			IngressRuleValue: netv1.IngressRuleValue{
+				HTTP: &netv1.HTTPIngressRuleValue{
+					Paths: []netv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend:  backend,
+					}},
+				},
+			},
+		})
+	}
+	return rules
+}
+
+func nginxIngressAnnotations(options manifest.ServiceExposeHTTPOptions) map[string]string {
+	readTimeout := math.Ceil(float64(options.ReadTimeout) / 1000.0)
+	sendTimeout := math.Ceil(float64(options.SendTimeout) / 1000.0)
+	nextTimeout := math.Ceil(float64(options.NextTimeout) / 1000.0)
+
+	nextCases := make([]string, 0, len(options.NextCases))
+	for _, nextCase := range options.NextCases {
+		// http codes are prefixed, the cases are separated by spaces
+		if len(nextCase) > 0 && strings.ContainsAny(nextCase[:1], "12345") {
+			nextCase = "http_" + nextCase
+		}
+		nextCases = append(nextCases, nextCase)
+	}
+
+	return map[string]string{
+		nginxAnnotationRoot + "/proxy-body-size":             strconv.Itoa(int(options.MaxBodySize)),
+		nginxAnnotationRoot + "/proxy-read-timeout":          strconv.Itoa(int(readTimeout)),
+		nginxAnnotationRoot + "/proxy-send-timeout":          strconv.Itoa(int(sendTimeout)),
+		nginxAnnotationRoot + "/proxy-next-upstream-tries":   strconv.Itoa(int(options.NextTries)),
+		nginxAnnotationRoot + "/proxy-next-upstream-timeout": strconv.Itoa(int(nextTimeout)),
+		nginxAnnotationRoot + "/proxy-next-upstream":         strings.Join(nextCases, " "),
+	}
+}
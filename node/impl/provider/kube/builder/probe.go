@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func buildProbe(probe *manifest.ServiceProbe) *corev1.Probe {
+	if probe == nil {
+		return nil
+	}
+
+	kprobe := &corev1.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+
+	switch probe.Type {
+	case manifest.ProbeHTTPGet:
+		scheme := corev1.URISchemeHTTP
+		if strings.EqualFold(probe.Scheme, string(corev1.URISchemeHTTPS)) {
+			scheme = corev1.URISchemeHTTPS
+		}
+
+		kprobe.HTTPGet = &corev1.HTTPGetAction{
+			Path:   probe.Path,
+			Port:   intstr.FromInt(int(probe.Port)),
+			Scheme: scheme,
+		}
+	case manifest.ProbeTCPSocket:
+		kprobe.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt(int(probe.Port)),
+		}
+	case manifest.ProbeExec:
+		kprobe.Exec = &corev1.ExecAction{
+			Command: probe.Command,
+		}
+	default:
+		return nil
+	}
+
+	return kprobe
+}
@@ -0,0 +1,93 @@
+package builder
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RegistrySecretName returns the name of the secret holding the registry credentials of the service
+func RegistrySecretName(serviceName string) string {
+	return fmt.Sprintf("%s-registry", serviceName)
+}
+
+type registrySecret struct {
+	Workload
+}
+
+var _ Secret = (*registrySecret)(nil)
+
+// BuildRegistrySecret stores the registry credentials of the service, the pods of the
+// service pull their image with it
+func BuildRegistrySecret(workload Workload) Secret {
+	return &registrySecret{Workload: workload}
+}
+
+func (b *registrySecret) Name() string {
+	return RegistrySecretName(b.Workload.Name())
+}
+
+// Any returns whether the service has registry credentials
+func (b *registrySecret) Any() bool {
+	return b.deployment.ManifestGroup().Services[b.serviceIdx].Credentials != nil
+}
+
+func (b *registrySecret) Create() (*corev1.Secret, error) {
+	data, err := b.data()
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: data,
+	}, nil
+}
+
+func (b *registrySecret) Update(obj *corev1.Secret) (*corev1.Secret, error) {
+	data, err := b.data()
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Labels = b.labels()
+	obj.Type = corev1.SecretTypeDockerConfigJson
+	obj.Data = data
+	return obj, nil
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+func (b *registrySecret) data() (map[string][]byte, error) {
+	credentials := b.deployment.ManifestGroup().Services[b.serviceIdx].Credentials
+
+	config := dockerConfigJSON{Auths: map[string]dockerConfigEntry{
+		credentials.Server: {
+			Username: credentials.Username,
+			Password: credentials.Password,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(credentials.Username + ":" + credentials.Password)),
+		},
+	}}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string][]byte{corev1.DockerConfigJsonKey: configJSON}, nil
+}
@@ -49,6 +49,10 @@ func makeGlobalServiceNameFromBasename(basename string) string {
 
 func (b *service) workloadServiceType() corev1.ServiceType {
 	if b.requireNodePort {
+		// load balancers also allocate node ports
+		if b.settings.DeploymentServiceType == corev1.ServiceTypeLoadBalancer {
+			return corev1.ServiceTypeLoadBalancer
+		}
 		return corev1.ServiceTypeNodePort
 	}
 	return corev1.ServiceTypeClusterIP
@@ -11,8 +11,7 @@ import (
 // cluster environment that is being used.
 // For instance, GCP requires a different service type than minikube.
 type Settings struct {
-	// gcp:    NodePort
-	// others: ClusterIP
+	// type of the services of the global ports, NodePort unless LoadBalancer
 	DeploymentServiceType corev1.ServiceType
 
 	// gcp:    false
@@ -21,6 +20,9 @@ type Settings struct {
 	// Ingress domain to map deployments to
 	DeploymentIngressDomain string
 
+	// cert-manager ClusterIssuer of the certificates of the ingress hosts
+	DeploymentIngressClusterIssuer string
+
 	// Return load balancer host in lease status command ?
 	// gcp:    true
 	// others: optional
@@ -46,12 +48,30 @@ type Settings struct {
 var ErrSettingsValidation = xerrors.New("settings validation")
 
 func ValidateSettings(settings Settings) error {
+	switch settings.DeploymentServiceType {
+	case "", corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+	default:
+		return fmt.Errorf("%w: unsupported service type %q", ErrSettingsValidation, settings.DeploymentServiceType)
+	}
+
+	commitLevels := map[string]float64{
+		"cpu":     settings.CPUCommitLevel,
+		"gpu":     settings.GPUCommitLevel,
+		"memory":  settings.MemoryCommitLevel,
+		"storage": settings.StorageCommitLevel,
+	}
+	for name, level := range commitLevels {
+		if level < 0 {
+			return fmt.Errorf("%w: negative %s commit level %v", ErrSettingsValidation, name, level)
+		}
+	}
+
 	if settings.DeploymentIngressStaticHosts {
 		if settings.DeploymentIngressDomain == "" {
 			return fmt.Errorf("%w: empty ingress domain", ErrSettingsValidation)
 		}
 
-		if isDomainName(settings.DeploymentIngressDomain) {
+		if !isDomainName(settings.DeploymentIngressDomain) {
 			return fmt.Errorf("%w: invalid domain name %q", ErrSettingsValidation, settings.DeploymentIngressDomain)
 		}
 	}
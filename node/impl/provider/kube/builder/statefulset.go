@@ -40,7 +40,8 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 			Replicas: b.replicas(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: b.labels(),
+					Labels:      b.labels(),
+					Annotations: b.podAnnotations(nil),
 				},
 				Spec: corev1.PodSpec{
 					SecurityContext: &corev1.PodSecurityContext{
@@ -49,6 +50,8 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 					AutomountServiceAccountToken: &falseValue,
 					Containers:                   []corev1.Container{b.container()},
 					ImagePullSecrets:             b.imagePullSecrets(),
+					RuntimeClassName:             b.runtimeClassName(),
+					Volumes:                      b.filesVolumes(),
 				},
 			},
 			VolumeClaimTemplates: b.persistentVolumeClaims(),
@@ -61,11 +64,17 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 func (b *statefulSet) Update(obj *appsv1.StatefulSet) (*appsv1.StatefulSet, error) { // nolint:golint,unparam
 	obj.Labels = b.labels()
 	obj.Spec.Selector.MatchLabels = b.labels()
-	obj.Spec.Replicas = b.replicas()
+	if !b.autoscaled() {
+		// the replicas of autoscaled services belong to their autoscaler
+		obj.Spec.Replicas = b.replicas()
+	}
 	obj.Spec.Template.Labels = b.labels()
+	obj.Spec.Template.Annotations = b.podAnnotations(obj.Spec.Template.Annotations)
 	obj.Spec.Template.Spec.Containers = []corev1.Container{b.container()}
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
-	obj.Spec.VolumeClaimTemplates = b.persistentVolumeClaims()
+	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClassName()
+	obj.Spec.Template.Spec.Volumes = b.filesVolumes()
+	// the volume claim templates of a stateful set are immutable, the volumes keep their initial spec
 
 	return obj, nil
 }
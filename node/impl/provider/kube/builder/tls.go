@@ -0,0 +1,75 @@
+package builder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// annotation of cert-manager issuing the certificates of the ingress tls section
+	certManagerClusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
+
+	nginxSSLRedirectAnnotation = nginxAnnotationRoot + "/ssl-redirect"
+)
+
+// TLSSecretName returns the name of the secret holding the certificate of the hosts of the service
+func TLSSecretName(serviceName string) string {
+	return fmt.Sprintf("%s-tls", serviceName)
+}
+
+type Secret interface {
+	workloadBase
+	Create() (*corev1.Secret, error)
+	Update(obj *corev1.Secret) (*corev1.Secret, error)
+	Any() bool
+}
+
+type tlsSecret struct {
+	Workload
+}
+
+var _ Secret = (*tlsSecret)(nil)
+
+// BuildTLSSecret stores the certificate supplied with the service. Issued certificates
+// are stored under the same name by cert-manager.
+func BuildTLSSecret(workload Workload) Secret {
+	return &tlsSecret{Workload: workload}
+}
+
+func (b *tlsSecret) Name() string {
+	return TLSSecretName(b.Workload.Name())
+}
+
+// Any returns whether the service supplies its certificate
+func (b *tlsSecret) Any() bool {
+	tls := b.deployment.ManifestGroup().Services[b.serviceIdx].TLS
+	return tls != nil && len(tls.Certificate) > 0
+}
+
+func (b *tlsSecret) Create() (*corev1.Secret, error) { // nolint:golint,unparam
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: b.data(),
+	}, nil
+}
+
+func (b *tlsSecret) Update(obj *corev1.Secret) (*corev1.Secret, error) { // nolint:golint,unparam
+	obj.Labels = b.labels()
+	obj.Type = corev1.SecretTypeTLS
+	obj.Data = b.data()
+	return obj, nil
+}
+
+func (b *tlsSecret) data() map[string][]byte {
+	tls := b.deployment.ManifestGroup().Services[b.serviceIdx].TLS
+	return map[string][]byte{
+		corev1.TLSCertKey:       tls.Certificate,
+		corev1.TLSPrivateKeyKey: tls.Key,
+	}
+}
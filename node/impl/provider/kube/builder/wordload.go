@@ -15,6 +15,7 @@ import (
 
 const (
 	StorageAttributePersistent = "persistent"
+	StorageAttributeClass      = "class"
 	StorageClassDefault        = "default"
 )
 
@@ -25,7 +26,8 @@ type workloadBase interface {
 
 type Workload struct {
 	builder
-	serviceIdx int
+	serviceIdx       int
+	secretEnvVersion string
 }
 
 var _ workloadBase = (*Workload)(nil)
@@ -51,6 +53,10 @@ func (b *Workload) replicas() *int32 {
 	return replicas
 }
 
+func (b *Workload) autoscaled() bool {
+	return b.deployment.ManifestGroup().Services[b.serviceIdx].Autoscale != nil
+}
+
 func (b *Workload) labels() map[string]string {
 	obj := b.builder.labels()
 	obj[TitanManifestServiceLabelName] = b.deployment.ManifestGroup().Services[b.serviceIdx].Name
@@ -58,11 +64,47 @@ func (b *Workload) labels() map[string]string {
 }
 
 func (b *Workload) imagePullSecrets() []corev1.LocalObjectReference {
-	if b.settings.DockerImagePullSecretsName == "" {
+	var secrets []corev1.LocalObjectReference
+	if b.settings.DockerImagePullSecretsName != "" {
+		secrets = append(secrets, corev1.LocalObjectReference{Name: b.settings.DockerImagePullSecretsName})
+	}
+
+	if b.deployment.ManifestGroup().Services[b.serviceIdx].Credentials != nil {
+		secrets = append(secrets, corev1.LocalObjectReference{Name: RegistrySecretName(b.Name())})
+	}
+
+	return secrets
+}
+
+func (b *Workload) runtimeClassName() *string {
+	if b.settings.DeploymentRuntimeClass == "" {
 		return nil
 	}
 
-	return []corev1.LocalObjectReference{{Name: b.settings.DockerImagePullSecretsName}}
+	runtimeClass := b.settings.DeploymentRuntimeClass
+	return &runtimeClass
+}
+
+// podAnnotations sets the annotations of the pod template owned by the builder, the
+// other annotations are kept
+func (b *Workload) podAnnotations(annotations map[string]string) map[string]string {
+	hashes := map[string]string{
+		TitanSecretEnvVersionAnnotationName: b.secretEnvVersion,
+		TitanFilesHashAnnotationName:        b.filesHash(),
+	}
+
+	for name, hash := range hashes {
+		if hash == "" {
+			delete(annotations, name)
+			continue
+		}
+
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[name] = hash
+	}
+	return annotations
 }
 
 func (b *Workload) container() corev1.Container {
@@ -123,6 +165,8 @@ func (b *Workload) container() corev1.Container {
 		}
 	}
 
+	kcontainer.VolumeMounts = append(kcontainer.VolumeMounts, b.filesVolumeMounts()...)
+
 	envVarsAdded := make(map[string]int)
 	for _, env := range service.Env {
 		parts := strings.SplitN(env, "=", 2)
@@ -134,6 +178,7 @@ func (b *Workload) container() corev1.Container {
 		}
 		envVarsAdded[parts[0]] = 0
 	}
+	kcontainer.Env = append(kcontainer.Env, b.secretEnvVars()...)
 	kcontainer.Env = b.addEnvVarsForDeployment(envVarsAdded, kcontainer.Env)
 
 	for _, expose := range service.Expose {
@@ -142,6 +187,10 @@ func (b *Workload) container() corev1.Container {
 		})
 	}
 
+	kcontainer.LivenessProbe = buildProbe(service.LivenessProbe)
+	kcontainer.ReadinessProbe = buildProbe(service.ReadinessProbe)
+	kcontainer.StartupProbe = buildProbe(service.StartupProbe)
+
 	buf, err := json.Marshal(kcontainer)
 	if err != nil {
 		fmt.Printf("Marshal err %s", err.Error())
@@ -208,7 +257,7 @@ func (b *Workload) persistentVolumeClaims() []corev1.PersistentVolumeClaim {
 
 		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.NewQuantity(int64(storage.Quantity.Val.Uint64()), resource.DecimalSI).DeepCopy()
 
-		attr = storage.Attributes.Find(StorageAttributePersistent)
+		attr = storage.Attributes.Find(StorageAttributeClass)
 		if class, valid := attr.AsString(); valid && class != StorageClassDefault {
 			pvc.Spec.StorageClassName = &class
 		}
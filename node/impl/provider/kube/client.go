@@ -2,36 +2,59 @@ package kube
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
 	logging "github.com/ipfs/go-log/v2"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/client-go/util/flowcontrol"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 )
 
 type Client interface {
 	Deploy(ctx context.Context, deployment builder.IClusterDeployment) error
 	GetNS(ctx context.Context, ns string) (*v1.Namespace, error)
+	ListNS(ctx context.Context, opts metav1.ListOptions) (*v1.NamespaceList, error)
 	DeleteNS(ctx context.Context, ns string) error
 	FetchNodeResources(ctx context.Context) (map[string]*nodeResource, error)
 	ListDeployments(ctx context.Context, ns string) (*appsv1.DeploymentList, error)
 	ListServices(ctx context.Context, ns string) (*corev1.ServiceList, error)
 	ListPods(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.PodList, error)
-	PodLogs(ctx context.Context, ns string, podName string) (io.ReadCloser, error)
+	PodLogs(ctx context.Context, ns string, podName string, opts *corev1.PodLogOptions) (io.ReadCloser, error)
 	Events(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.EventList, error)
+	WatchEvents(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error)
+	GetPod(ctx context.Context, ns string, name string) (*corev1.Pod, error)
+	Exec(ctx context.Context, ns string, podName string, opts *corev1.PodExecOptions, streams remotecommand.StreamOptions) error
+	Scale(ctx context.Context, ns string, name string, replicas int32) error
+	PodMetrics(ctx context.Context, ns string) (*metricsv1beta1.PodMetricsList, error)
+	ListStatefulSets(ctx context.Context, ns string) (*appsv1.StatefulSetList, error)
+	ListIngresses(ctx context.Context, ns string) (*netv1.IngressList, error)
+	GetSecret(ctx context.Context, ns string, name string) (*corev1.Secret, error)
+	Restart(ctx context.Context, ns string, name string, restartedAt time.Time) (*corev1.ObjectReference, error)
+	RolloutStatus(ctx context.Context, ref *corev1.ObjectReference) (string, bool, error)
+	RecordEvent(ctx context.Context, ref *corev1.ObjectReference, eventType, reason, message string) error
 }
 
 type client struct {
+	cfg  *rest.Config
 	kc   kubernetes.Interface
 	metc metricsclient.Interface
 	log  *logging.ZapEventLogger
@@ -82,7 +105,7 @@ func NewClient(configPath string) (Client, error) {
 
 	var log = logging.Logger("client")
 
-	return &client{kc: clientSet, metc: metc, log: log}, nil
+	return &client{cfg: config, kc: clientSet, metc: metc, log: log}, nil
 }
 
 func (c *client) Deploy(ctx context.Context, deployment builder.IClusterDeployment) error {
@@ -97,6 +120,12 @@ func (c *client) Deploy(ctx context.Context, deployment builder.IClusterDeployme
 		return err
 	}
 
+	for svcIdx := range group.Services {
+		if err := builder.BuildIngress(builder.NewWorkload(settings, deployment, svcIdx)).Validate(); err != nil {
+			return err
+		}
+	}
+
 	ns := builder.BuildNS(settings, deployment)
 	if err := applyNS(ctx, c.kc, builder.BuildNS(settings, deployment)); err != nil {
 		c.log.Errorf("applying namespace %s err %s", ns.Name(), err.Error())
@@ -113,24 +142,117 @@ func (c *client) Deploy(ctx context.Context, deployment builder.IClusterDeployme
 
 		service := &group.Services[svcIdx]
 
+		// the pods pull their image with the registry secret, it is applied first
+		registrySecret := builder.BuildRegistrySecret(workload)
+		if registrySecret.Any() {
+			if _, err := applySecret(ctx, c.kc, registrySecret); err != nil {
+				c.log.Errorf("applying registry secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		} else if err := deleteSecret(ctx, c.kc, registrySecret); err != nil {
+			c.log.Errorf("deleting registry secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+			return err
+		}
+
+		// the containers reference the env secret, it is applied before and deleted after the workload.
+		// Its resource version changes with the values, the pods are restarted then.
+		envSecret := builder.BuildEnvSecret(workload)
+		if envSecret.Any() {
+			obj, err := applySecret(ctx, c.kc, envSecret)
+			if err != nil {
+				c.log.Errorf("applying env secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+			workload.SetSecretEnvVersion(obj.ResourceVersion)
+		}
+
+		// likewise for the config map of the files mounted in the containers
+		configMap := builder.BuildConfigMap(workload)
+		if configMap.Any() {
+			if err := applyConfigMap(ctx, c.kc, configMap); err != nil {
+				c.log.Errorf("applying config map err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		}
+
 		persistent := false
 		for i := range service.Resources.Storage {
-			attrVal := service.Resources.Storage[i].Attributes.Find(builder.StorageClassDefault)
+			attrVal := service.Resources.Storage[i].Attributes.Find(builder.StorageAttributePersistent)
 			if persistent, _ = attrVal.AsBool(); persistent {
 				break
 			}
 		}
 
+		// a service gaining or losing its volumes changes of workload kind, the other kind is deleted
+		scaleTarget := builder.ScaleTargetDeployment
 		if persistent {
+			scaleTarget = builder.ScaleTargetStatefulSet
 			if err := applyStatefulSet(ctx, c.kc, builder.BuildStatefulSet(workload)); err != nil {
 				c.log.Errorf("applying statefulSet err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
 				return err
 			}
+			if err := deleteDeployment(ctx, c.kc, builder.NewDeployment(workload)); err != nil {
+				c.log.Errorf("deleting deployment err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
 		} else {
 			if err := applyDeployment(ctx, c.kc, builder.NewDeployment(workload)); err != nil {
 				c.log.Errorf("applying deployment err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
 				return err
 			}
+			if err := deleteStatefulSet(ctx, c.kc, builder.BuildStatefulSet(workload)); err != nil {
+				c.log.Errorf("deleting statefulSet err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		}
+
+		if !envSecret.Any() {
+			if err := deleteSecret(ctx, c.kc, envSecret); err != nil {
+				c.log.Errorf("deleting env secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		}
+
+		if !configMap.Any() {
+			if err := deleteConfigMap(ctx, c.kc, configMap); err != nil {
+				c.log.Errorf("deleting config map err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		}
+
+		hpa := builder.BuildHorizontalPodAutoscaler(workload, scaleTarget)
+		if hpa.Any() {
+			if err := applyHorizontalPodAutoscaler(ctx, c.kc, hpa); err != nil {
+				c.log.Errorf("applying horizontal pod autoscaler err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		} else if err := deleteHorizontalPodAutoscaler(ctx, c.kc, hpa); err != nil {
+			c.log.Errorf("deleting horizontal pod autoscaler err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+			return err
+		}
+
+		tlsSecret := builder.BuildTLSSecret(workload)
+		if tlsSecret.Any() {
+			if _, err := applySecret(ctx, c.kc, tlsSecret); err != nil {
+				c.log.Errorf("applying tls secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		} else if service.TLS == nil {
+			if err := deleteSecret(ctx, c.kc, tlsSecret); err != nil {
+				c.log.Errorf("deleting tls secret err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		}
+
+		ingress := builder.BuildIngress(workload)
+		if ingress.Any() {
+			if err := applyIngress(ctx, c.kc, ingress); err != nil {
+				c.log.Errorf("applying ingress err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+				return err
+			}
+		} else if err := deleteIngress(ctx, c.kc, ingress); err != nil {
+			c.log.Errorf("deleting ingress err %s, ns %s, service %s", err.Error(), ns.Name(), service.Name)
+			return err
 		}
 
 		if len(service.Expose) == 0 {
@@ -155,6 +277,11 @@ func (c *client) Deploy(ctx context.Context, deployment builder.IClusterDeployme
 		}
 	}
 
+	if err := pruneServices(ctx, c.kc, ns.Name(), group); err != nil {
+		c.log.Errorf("pruning removed services err %s, ns %s", err.Error(), ns.Name())
+		return err
+	}
+
 	return nil
 }
 
@@ -166,6 +293,10 @@ func (c *client) GetNS(ctx context.Context, ns string) (*v1.Namespace, error) {
 	return c.kc.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
 }
 
+func (c *client) ListNS(ctx context.Context, opts metav1.ListOptions) (*v1.NamespaceList, error) {
+	return c.kc.CoreV1().Namespaces().List(ctx, opts)
+}
+
 func (c *client) ListServices(ctx context.Context, ns string) (*corev1.ServiceList, error) {
 	return c.kc.CoreV1().Services(ns).List(ctx, metav1.ListOptions{})
 }
@@ -174,14 +305,195 @@ func (c *client) ListDeployments(ctx context.Context, ns string) (*appsv1.Deploy
 	return c.kc.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
 }
 
+func (c *client) ListIngresses(ctx context.Context, ns string) (*netv1.IngressList, error) {
+	return c.kc.NetworkingV1().Ingresses(ns).List(ctx, metav1.ListOptions{})
+}
+
+func (c *client) GetSecret(ctx context.Context, ns string, name string) (*corev1.Secret, error) {
+	return c.kc.CoreV1().Secrets(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *client) ListStatefulSets(ctx context.Context, ns string) (*appsv1.StatefulSetList, error) {
+	return c.kc.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{})
+}
+
 func (c *client) ListPods(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.PodList, error) {
 	return c.kc.CoreV1().Pods(ns).List(ctx, opts)
 }
 
-func (c *client) PodLogs(ctx context.Context, ns string, podName string) (io.ReadCloser, error) {
-	return c.kc.CoreV1().Pods(ns).GetLogs(podName, &corev1.PodLogOptions{}).Stream(context.Background())
+func (c *client) PodLogs(ctx context.Context, ns string, podName string, opts *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return c.kc.CoreV1().Pods(ns).GetLogs(podName, opts).Stream(ctx)
 }
 
 func (c *client) Events(ctx context.Context, ns string, opts metav1.ListOptions) (*corev1.EventList, error) {
 	return c.kc.CoreV1().Events(ns).List(ctx, opts)
 }
+
+func (c *client) WatchEvents(ctx context.Context, ns string, opts metav1.ListOptions) (watch.Interface, error) {
+	return c.kc.CoreV1().Events(ns).Watch(ctx, opts)
+}
+
+func (c *client) GetPod(ctx context.Context, ns string, name string) (*corev1.Pod, error) {
+	return c.kc.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{})
+}
+
+// Exec runs a command in the pod and streams its input and output until it exits
+func (c *client) Exec(ctx context.Context, ns string, podName string, opts *corev1.PodExecOptions, streams remotecommand.StreamOptions) error {
+	req := c.kc.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(ns).
+		SubResource("exec").
+		VersionedParams(opts, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.cfg, http.MethodPost, req.URL())
+	if err != nil {
+		return err
+	}
+
+	return executor.StreamWithContext(ctx, streams)
+}
+
+// PodMetrics returns the latest resource usage of the pods sampled by the metrics server
+func (c *client) PodMetrics(ctx context.Context, ns string) (*metricsv1beta1.PodMetricsList, error) {
+	return c.metc.MetricsV1beta1().PodMetricses(ns).List(ctx, metav1.ListOptions{})
+}
+
+// Scale sets the replicas of the deployment or the stateful set through its scale subresource,
+// leaving the pod template untouched
+func (c *client) Scale(ctx context.Context, ns string, name string, replicas int32) error {
+	scale, err := c.kc.AppsV1().Deployments(ns).GetScale(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		scale.Spec.Replicas = replicas
+		_, err = c.kc.AppsV1().Deployments(ns).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+		return err
+	}
+
+	if !kerrors.IsNotFound(err) {
+		return err
+	}
+
+	scale, err = c.kc.AppsV1().StatefulSets(ns).GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	scale.Spec.Replicas = replicas
+	_, err = c.kc.AppsV1().StatefulSets(ns).UpdateScale(ctx, name, scale, metav1.UpdateOptions{})
+	return err
+}
+
+// Restart triggers a rolling restart of the deployment or the stateful set by changing
+// an annotation of its pod template, and returns a reference to the restarted workload
+func (c *client) Restart(ctx context.Context, ns string, name string, restartedAt time.Time) (*corev1.ObjectReference, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						builder.TitanRestartedAtAnnotationName: restartedAt.Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := c.kc.AppsV1().Deployments(ns).Patch(ctx, name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err == nil {
+		return &corev1.ObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Namespace: ns, Name: name, UID: deployment.UID}, nil
+	}
+
+	if !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	statefulSet, err := c.kc.AppsV1().StatefulSets(ns).Patch(ctx, name, k8stypes.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ObjectReference{Kind: "StatefulSet", APIVersion: "apps/v1", Namespace: ns, Name: name, UID: statefulSet.UID}, nil
+}
+
+// RolloutStatus describes the progress of the rollout of the workload, and whether it is complete
+func (c *client) RolloutStatus(ctx context.Context, ref *corev1.ObjectReference) (string, bool, error) {
+	switch ref.Kind {
+	case "Deployment":
+		deployment, err := c.kc.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", false, err
+		}
+
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+
+		status := deployment.Status
+		if deployment.Generation > status.ObservedGeneration {
+			return "waiting for the rollout to start", false, nil
+		}
+		if status.UpdatedReplicas < replicas {
+			return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas), false, nil
+		}
+		if status.Replicas > status.UpdatedReplicas {
+			return fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas), false, nil
+		}
+		if status.AvailableReplicas < status.UpdatedReplicas {
+			return fmt.Sprintf("%d of %d updated replicas available", status.AvailableReplicas, status.UpdatedReplicas), false, nil
+		}
+		return fmt.Sprintf("%d replicas restarted", status.UpdatedReplicas), true, nil
+	case "StatefulSet":
+		statefulSet, err := c.kc.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", false, err
+		}
+
+		replicas := int32(1)
+		if statefulSet.Spec.Replicas != nil {
+			replicas = *statefulSet.Spec.Replicas
+		}
+
+		status := statefulSet.Status
+		if statefulSet.Generation > status.ObservedGeneration {
+			return "waiting for the rollout to start", false, nil
+		}
+		if status.UpdatedReplicas < replicas {
+			return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas), false, nil
+		}
+		if status.ReadyReplicas < replicas {
+			return fmt.Sprintf("%d of %d replicas ready", status.ReadyReplicas, replicas), false, nil
+		}
+		if status.UpdateRevision != status.CurrentRevision {
+			return "waiting for the update revision to become current", false, nil
+		}
+		return fmt.Sprintf("%d replicas restarted", replicas), true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported workload kind %s", ref.Kind)
+	}
+}
+
+// RecordEvent records an event about the object, in the namespace of the object
+func (c *client) RecordEvent(ctx context.Context, ref *corev1.ObjectReference, eventType, reason, message string) error {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ref.Name + ".",
+			Namespace:    ref.Namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Source:         corev1.EventSource{Component: "titan-provider"},
+	}
+
+	_, err := c.kc.CoreV1().Events(ref.Namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
@@ -0,0 +1,27 @@
+package manifest
+
+type ProbeType string
+
+const (
+	ProbeHTTPGet   ProbeType = "http"
+	ProbeTCPSocket ProbeType = "tcp"
+	ProbeExec      ProbeType = "exec"
+)
+
+// ServiceProbe is a health check of the container of a service
+type ServiceProbe struct {
+	Type ProbeType
+	// path and scheme of the http probes
+	Path   string
+	Scheme string
+	// port of the http and tcp probes
+	Port uint32
+	// command of the exec probes
+	Command []string
+
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	TimeoutSeconds      int32
+	SuccessThreshold    int32
+	FailureThreshold    int32
+}
@@ -1,10 +1,10 @@
 package manifest
 
 type ServiceExposeHTTPOptions struct {
-	maxBodySize uint32
-	readTimeout uint32
+	MaxBodySize uint32
+	ReadTimeout uint32
 	SendTimeout uint32
-	nextTries   uint32
-	nextTimeout uint32
-	nextCases   []string
+	NextTries   uint32
+	NextTimeout uint32
+	NextCases   []string
 }
@@ -8,6 +8,50 @@ type Service struct {
 	Env       []string
 	Resources *ResourceUnits
 	Count     int32
+	Autoscale *ServiceAutoscale
 	Expose    []*ServiceExpose
 	Params    *ServiceParams
+
+	LivenessProbe  *ServiceProbe
+	ReadinessProbe *ServiceProbe
+	StartupProbe   *ServiceProbe
+
+	TLS *ServiceTLS
+
+	Credentials *ServiceCredentials
+
+	// SecretEnv is kept in a secret of the service instead of the pod spec
+	SecretEnv map[string]string
+
+	Files []ServiceFile
+}
+
+// ServiceFile is mounted read only in the container from the config map of the service
+type ServiceFile struct {
+	Path    string
+	Content string
+	Mode    int32
+}
+
+// ServiceCredentials authenticates the pulls of the image from a private registry
+type ServiceCredentials struct {
+	Server   string
+	Username string
+	Password string
+}
+
+// ServiceTLS serves the ingress hosts of the service over https. The PEM encoded
+// certificate and key are issued by the cluster issuer of the provider when empty.
+type ServiceTLS struct {
+	Certificate []byte
+	Key         []byte
+}
+
+// ServiceAutoscale scales the service between MinReplicas and MaxReplicas to keep the
+// average utilisation of the requested cpu and memory at the targets, in percent
+type ServiceAutoscale struct {
+	MinReplicas       int32
+	MaxReplicas       int32
+	CPUUtilization    int32
+	MemoryUtilization int32
 }
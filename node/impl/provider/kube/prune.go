@@ -0,0 +1,136 @@
+package kube
+
+import (
+	"context"
+
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+// pruneServices deletes the objects of the services which are not in the group anymore,
+// after they were removed by an update or a rollback
+func pruneServices(ctx context.Context, kc kubernetes.Interface, ns string, group *manifest.Group) error {
+	names := make([]string, 0, len(group.Services))
+	for _, service := range group.Services {
+		names = append(names, service.Name)
+	}
+
+	// notin alone matches the objects without the label, like the root ca of the namespace
+	exists, err := labels.NewRequirement(builder.TitanManifestServiceLabelName, selection.Exists, nil)
+	if err != nil {
+		return err
+	}
+	notIn, err := labels.NewRequirement(builder.TitanManifestServiceLabelName, selection.NotIn, names)
+	if err != nil {
+		return err
+	}
+	opts := metav1.ListOptions{LabelSelector: labels.NewSelector().Add(*exists, *notIn).String()}
+
+	removed := make(map[string]struct{})
+	deleteOpts := metav1.DeleteOptions{}
+
+	deployments, err := kc.AppsV1().Deployments(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range deployments.Items {
+		removed[obj.Labels[builder.TitanManifestServiceLabelName]] = struct{}{}
+		if err := ignoreNotFound(kc.AppsV1().Deployments(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	statefulSets, err := kc.AppsV1().StatefulSets(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range statefulSets.Items {
+		removed[obj.Labels[builder.TitanManifestServiceLabelName]] = struct{}{}
+		if err := ignoreNotFound(kc.AppsV1().StatefulSets(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	hpas, err := kc.AutoscalingV2().HorizontalPodAutoscalers(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range hpas.Items {
+		if err := ignoreNotFound(kc.AutoscalingV2().HorizontalPodAutoscalers(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	services, err := kc.CoreV1().Services(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range services.Items {
+		if err := ignoreNotFound(kc.CoreV1().Services(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	ingresses, err := kc.NetworkingV1().Ingresses(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range ingresses.Items {
+		removed[obj.Labels[builder.TitanManifestServiceLabelName]] = struct{}{}
+		if err := ignoreNotFound(kc.NetworkingV1().Ingresses(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	secrets, err := kc.CoreV1().Secrets(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range secrets.Items {
+		if err := ignoreNotFound(kc.CoreV1().Secrets(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	// the certificates issued by cert-manager are stored without the labels of the service
+	for name := range removed {
+		if err := ignoreNotFound(kc.CoreV1().Secrets(ns).Delete(ctx, builder.TLSSecretName(name), deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	configMaps, err := kc.CoreV1().ConfigMaps(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range configMaps.Items {
+		if err := ignoreNotFound(kc.CoreV1().ConfigMaps(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	// the claims of the stateful sets carry the labels of their selector
+	claims, err := kc.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, obj := range claims.Items {
+		if err := ignoreNotFound(kc.CoreV1().PersistentVolumeClaims(ns).Delete(ctx, obj.Name, deleteOpts)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	logsBufferSize = 256
+	maxLogLineSize = 1024 * 1024
+)
+
+// StreamLogs streams the logs of the pods of the deployment line by line.
+// The channel is closed once every pod log is read, or when the context is done.
+func (m *manager) StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error) {
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	pods, err := m.getPods(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	podLogOptions := &corev1.PodLogOptions{
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+		Previous:   opts.Previous,
+	}
+	if opts.TailLines > 0 {
+		podLogOptions.TailLines = &opts.TailLines
+	}
+	if opts.SinceSeconds > 0 {
+		podLogOptions.SinceSeconds = &opts.SinceSeconds
+	}
+
+	type podLog struct {
+		podName     string
+		serviceName string
+		reader      io.ReadCloser
+	}
+
+	var podLogs []podLog
+	for podName, serviceName := range pods {
+		if opts.Service != "" && opts.Service != serviceName {
+			continue
+		}
+
+		reader, err := m.kc.PodLogs(ctx, ns, podName, podLogOptions)
+		if err != nil {
+			for _, pl := range podLogs {
+				pl.reader.Close()
+			}
+			return nil, fmt.Errorf("stream logs of pod %s: %w", podName, err)
+		}
+		podLogs = append(podLogs, podLog{podName: podName, serviceName: serviceName, reader: reader})
+	}
+
+	if len(podLogs) == 0 && opts.Service != "" {
+		return nil, fmt.Errorf("service %s has no pod", opts.Service)
+	}
+
+	var wg sync.WaitGroup
+	out := make(chan types.LogLine, logsBufferSize)
+
+	for _, pl := range podLogs {
+		wg.Add(1)
+		go func(podName, serviceName string, reader io.ReadCloser) {
+			defer wg.Done()
+			defer reader.Close()
+
+			scanner := bufio.NewScanner(reader)
+			scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+			for scanner.Scan() {
+				line := types.LogLine{ServiceName: serviceName, PodName: podName, Line: types.Log(scanner.Text())}
+				select {
+				case out <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				log.Errorf("read logs of pod %s: %v", podName, err)
+			}
+		}(pl.podName, pl.serviceName, pl.reader)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out, nil
+}
@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/Filecoin-Titan/titan-container/api/types"
 	"github.com/Filecoin-Titan/titan-container/node/config"
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube"
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
 	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
 	logging "github.com/ipfs/go-log/v2"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -22,23 +28,40 @@ type Manager interface {
 	UpdateDeployment(ctx context.Context, deployment *types.Deployment) error
 	CloseDeployment(ctx context.Context, deployment *types.Deployment) error
 	GetDeployment(ctx context.Context, id types.DeploymentID) (*types.Deployment, error)
+	ListDeploymentIDs(ctx context.Context) ([]types.DeploymentID, error)
 	GetLogs(ctx context.Context, id types.DeploymentID) ([]*types.ServiceLog, error)
 	GetEvents(ctx context.Context, id types.DeploymentID) ([]*types.ServiceEvent, error)
+	WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error)
+	StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error)
+	ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error)
+	CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error
+	CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error)
+	ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error
+	RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error
+	GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error)
+	GetKubeSettings(ctx context.Context) (*types.KubeSettings, error)
 }
 
 type manager struct {
 	kc          kube.Client
 	providerCfg *config.ProviderCfg
+	getConfig   dtypes.GetProviderConfigFunc
 }
 
 var _ Manager = (*manager)(nil)
 
-func NewManager(config *config.ProviderCfg) (Manager, error) {
+// NewManager creates the manager of the deployments of the provider. The kube settings
+// are reloaded with getConfig when not nil, the startup config is used otherwise.
+func NewManager(config *config.ProviderCfg, getConfig dtypes.GetProviderConfigFunc) (Manager, error) {
+	if err := builder.ValidateSettings(kubeSettings(config.Kube)); err != nil {
+		return nil, xerrors.Errorf("invalid kube config: %w", err)
+	}
+
 	client, err := kube.NewClient(config.KubeConfigPath)
 	if err != nil {
 		return nil, err
 	}
-	return &manager{kc: client, providerCfg: config}, nil
+	return &manager{kc: client, providerCfg: config, getConfig: getConfig}, nil
 }
 
 func (m *manager) GetStatistics(ctx context.Context) (*types.ResourcesStatistics, error) {
@@ -83,17 +106,31 @@ func (m *manager) CreateDeployment(ctx context.Context, deployment *types.Deploy
 	did := k8sDeployment.DeploymentID()
 	ns := builder.DidNS(did)
 
-	deploymentList, err := m.kc.ListDeployments(context.Background(), ns)
+	workloads, err := m.workloadNames(context.Background(), ns)
 	if err != nil {
-		log.Errorf("ListDeployments %s", err.Error())
+		log.Errorf("ListWorkloads %s", err.Error())
 		return err
 	}
 
-	if deploymentList != nil && len(deploymentList.Items) > 0 {
+	if len(workloads) > 0 {
 		return fmt.Errorf("deployment %s already exist", deployment.ID)
 	}
 
-	ctx = context.WithValue(ctx, builder.SettingsKey, builder.NewDefaultSettings())
+	settings, err := m.settings()
+	if err != nil {
+		return err
+	}
+
+	ingresses, err := m.kc.ListIngresses(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+
+	if err := checkHosts(settings, ns, k8sDeployment.ManifestGroup(), ingresses); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, builder.SettingsKey, settings)
 	return m.kc.Deploy(ctx, k8sDeployment)
 }
 
@@ -107,19 +144,45 @@ func (m *manager) UpdateDeployment(ctx context.Context, deployment *types.Deploy
 	did := k8sDeployment.DeploymentID()
 	ns := builder.DidNS(did)
 
-	deploymentList, err := m.kc.ListDeployments(context.Background(), ns)
+	workloads, err := m.workloadNames(context.Background(), ns)
 	if err != nil {
 		return err
 	}
 
-	if deploymentList == nil || len(deploymentList.Items) == 0 {
+	if len(workloads) == 0 {
 		return fmt.Errorf("deployment %s do not exist", deployment.ID)
 	}
 
-	ctx = context.WithValue(ctx, builder.SettingsKey, builder.NewDefaultSettings())
+	settings, err := m.settings()
+	if err != nil {
+		return err
+	}
+
+	ingresses, err := m.kc.ListIngresses(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+
+	if err := checkHosts(settings, ns, k8sDeployment.ManifestGroup(), ingresses); err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, builder.SettingsKey, settings)
 	return m.kc.Deploy(ctx, k8sDeployment)
 }
 
+// ScaleDeployment changes the number of pods of a service, without rebuilding its pod template
+func (m *manager) ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error {
+	if replicas < 0 {
+		return fmt.Errorf("invalid replicas %d", replicas)
+	}
+
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	return m.kc.Scale(ctx, ns, service, int32(replicas))
+}
+
 func (m *manager) CloseDeployment(ctx context.Context, deployment *types.Deployment) error {
 	k8sDeployment, err := ClusterDeploymentFromDeployment(deployment)
 	if err != nil {
@@ -150,6 +213,18 @@ func (m *manager) GetDeployment(ctx context.Context, id types.DeploymentID) (*ty
 		return nil, err
 	}
 
+	// services with persistent volumes are deployed as stateful sets
+	statefulSetList, err := m.kc.ListStatefulSets(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulServices, err := k8sStatefulSetsToServices(statefulSetList)
+	if err != nil {
+		return nil, err
+	}
+	services = append(services, statefulServices...)
+
 	serviceList, err := m.kc.ListServices(ctx, ns)
 	if err != nil {
 		return nil, err
@@ -160,14 +235,76 @@ func (m *manager) GetDeployment(ctx context.Context, id types.DeploymentID) (*ty
 		return nil, err
 	}
 
+	ingressList, err := m.kc.ListIngresses(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := m.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	uriMap := k8sIngressToURIMap(ingressList, settings.DeploymentIngressExposeLBHosts)
+
 	for i := range services {
 		name := services[i].Name
 		if ports, ok := portMap[name]; ok {
 			services[i].Ports = ports
 		}
+		services[i].URIs = uriMap[name]
+	}
+
+	for _, ingress := range ingressList.Items {
+		if len(ingress.Spec.TLS) == 0 {
+			continue
+		}
+
+		status, err := m.certificateStatus(ctx, ns, ingress.Spec.TLS[0].SecretName)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range services {
+			if services[i].Name == ingress.Name {
+				services[i].Certificate = status
+			}
+		}
+	}
+
+	exposeIP := m.providerCfg.PublicIP
+	if settings.ClusterPublicHostname != "" {
+		exposeIP = settings.ClusterPublicHostname
+	}
+
+	return &types.Deployment{ID: id, Services: services, ProviderExposeIP: exposeIP}, nil
+}
+
+func (m *manager) certificateStatus(ctx context.Context, ns string, secretName string) (*types.CertificateStatus, error) {
+	secret, err := m.kc.GetSecret(ctx, ns, secretName)
+	if kerrors.IsNotFound(err) {
+		return &types.CertificateStatus{Message: "certificate not issued yet"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return certificateStatus(secret, time.Now()), nil
+}
+
+func (m *manager) ListDeploymentIDs(ctx context.Context) ([]types.DeploymentID, error) {
+	opts := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", builder.TitanManagedLabelName)}
+	nsList, err := m.kc.ListNS(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return &types.Deployment{ID: id, Services: services, ProviderExposeIP: m.providerCfg.PublicIP}, nil
+	ids := make([]types.DeploymentID, 0, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		ids = append(ids, types.DeploymentID(ns.Name))
+	}
+
+	return ids, nil
 }
 
 func (m *manager) GetLogs(ctx context.Context, id types.DeploymentID) ([]*types.ServiceLog, error) {
@@ -232,6 +369,13 @@ func (m *manager) GetEvents(ctx context.Context, id types.DeploymentID) ([]*type
 		serviceEventMap[serviceName] = es
 	}
 
+	// the workloads and the autoscalers are named after their service, and record the scaling events
+	for serviceName, es := range serviceEventMap {
+		if events, ok := podEventMap[serviceName]; ok {
+			serviceEventMap[serviceName] = append(es, events...)
+		}
+	}
+
 	serviceEvents := make([]*types.ServiceEvent, 0, len(serviceEventMap))
 	for serviceName, events := range serviceEventMap {
 		serviceEvent := &types.ServiceEvent{ServiceName: serviceName, Events: events}
@@ -241,51 +385,24 @@ func (m *manager) GetEvents(ctx context.Context, id types.DeploymentID) ([]*type
 	return serviceEvents, nil
 }
 
+// getPods returns the service names of the pods of the namespace, keyed by pod name.
+// It covers the pods of both deployments and stateful sets.
 func (m *manager) getPods(ctx context.Context, ns string) (map[string]string, error) {
-	deploymentList, err := m.kc.ListDeployments(context.Background(), ns)
+	podList, err := m.kc.ListPods(ctx, ns, metav1.ListOptions{LabelSelector: builder.TitanManifestServiceLabelName})
 	if err != nil {
 		return nil, err
 	}
 
-	if deploymentList == nil {
-		return nil, fmt.Errorf("namespace %s do not exist deployment", ns)
-	}
-
 	pods := make(map[string]string)
-	for _, deployment := range deploymentList.Items {
-		labels := deployment.ObjectMeta.Labels
-		podList, err := m.kc.ListPods(context.Background(), ns, labelsToListOptions(labels))
-		if err != nil {
-			return nil, err
-		}
-
-		if podList == nil {
-			continue
-		}
-
-		for _, pod := range podList.Items {
-			pods[pod.Name] = deployment.Name
-		}
+	for _, pod := range podList.Items {
+		pods[pod.Name] = pod.Labels[builder.TitanManifestServiceLabelName]
 	}
 
 	return pods, nil
 }
 
-func labelsToListOptions(labels map[string]string) metav1.ListOptions {
-	labelSelector := ""
-	for k, v := range labels {
-		if len(labelSelector) > 0 {
-			labelSelector = fmt.Sprintf("%s;%s=%s", labelSelector, k, v)
-		} else {
-			labelSelector = fmt.Sprintf("%s=%s", k, v)
-		}
-	}
-
-	return metav1.ListOptions{LabelSelector: labelSelector}
-}
-
 func (m *manager) getPodLogs(ctx context.Context, ns string, podName string) ([]byte, error) {
-	reader, err := m.kc.PodLogs(ctx, ns, podName)
+	reader, err := m.kc.PodLogs(ctx, ns, podName, &corev1.PodLogOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetDeploymentMetrics returns the resource usage of the pods of the deployment, grouped by service
+func (m *manager) GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	podList, err := m.kc.ListPods(ctx, ns, metav1.ListOptions{LabelSelector: builder.TitanManifestServiceLabelName})
+	if err != nil {
+		return nil, err
+	}
+
+	podMetricsList, err := m.kc.PodMetrics(ctx, ns)
+	if err != nil {
+		return nil, fmt.Errorf("get pod metrics, is the metrics server running? %w", err)
+	}
+
+	podMetrics := make(map[string]*types.PodMetrics, len(podMetricsList.Items))
+	for _, item := range podMetricsList.Items {
+		pm := &types.PodMetrics{PodName: item.Name, Timestamp: item.Timestamp.Time}
+		for _, container := range item.Containers {
+			pm.CPU += container.Usage.Cpu().MilliValue()
+			pm.Memory += container.Usage.Memory().Value()
+		}
+		podMetrics[item.Name] = pm
+	}
+
+	services := make(map[string]*types.ServiceMetrics)
+	for _, pod := range podList.Items {
+		serviceName := pod.Labels[builder.TitanManifestServiceLabelName]
+
+		pm, ok := podMetrics[pod.Name]
+		if !ok {
+			pm = &types.PodMetrics{PodName: pod.Name}
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			pm.Restarts += status.RestartCount
+		}
+
+		sm, ok := services[serviceName]
+		if !ok {
+			sm = &types.ServiceMetrics{ServiceName: serviceName}
+			services[serviceName] = sm
+		}
+		sm.CPU += pm.CPU
+		sm.Memory += pm.Memory
+		sm.Restarts += pm.Restarts
+		sm.Pods = append(sm.Pods, pm)
+	}
+
+	out := make([]*types.ServiceMetrics, 0, len(services))
+	for _, sm := range services {
+		sort.Slice(sm.Pods, func(i, j int) bool { return sm.Pods[i].PodName < sm.Pods[j].PodName })
+		out = append(out, sm)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ServiceName < out[j].ServiceName })
+
+	return out, nil
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"io"
 
 	"github.com/Filecoin-Titan/titan-container/api"
 	"github.com/Filecoin-Titan/titan-container/api/types"
@@ -36,6 +37,10 @@ func (p *Provider) GetDeployment(ctx context.Context, id types.DeploymentID) (*t
 	return p.Manager.GetDeployment(ctx, id)
 }
 
+func (p *Provider) ListDeploymentIDs(ctx context.Context) ([]types.DeploymentID, error) {
+	return p.Manager.ListDeploymentIDs(ctx)
+}
+
 func (p *Provider) CreateDeployment(ctx context.Context, deployment *types.Deployment) error {
 	return p.Manager.CreateDeployment(ctx, deployment)
 }
@@ -54,3 +59,39 @@ func (p *Provider) GetLogs(ctx context.Context, id types.DeploymentID) ([]*types
 func (p *Provider) GetEvents(ctx context.Context, id types.DeploymentID) ([]*types.ServiceEvent, error) {
 	return p.Manager.GetEvents(ctx, id)
 }
+
+func (p *Provider) StreamLogs(ctx context.Context, id types.DeploymentID, opts types.LogOptions) (<-chan types.LogLine, error) {
+	return p.Manager.StreamLogs(ctx, id, opts)
+}
+
+func (p *Provider) ExecDeployment(ctx context.Context, id types.DeploymentID, opts types.ExecOptions, stdin io.Reader) (<-chan types.ExecOutput, error) {
+	return p.Manager.ExecDeployment(ctx, id, opts, stdin)
+}
+
+func (p *Provider) GetDeploymentMetrics(ctx context.Context, id types.DeploymentID) ([]*types.ServiceMetrics, error) {
+	return p.Manager.GetDeploymentMetrics(ctx, id)
+}
+
+func (p *Provider) RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error {
+	return p.Manager.RestartDeployment(ctx, id, service)
+}
+
+func (p *Provider) ScaleDeployment(ctx context.Context, id types.DeploymentID, service string, replicas int) error {
+	return p.Manager.ScaleDeployment(ctx, id, service, replicas)
+}
+
+func (p *Provider) CopyToDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions, archive io.Reader) error {
+	return p.Manager.CopyToDeployment(ctx, id, opts, archive)
+}
+
+func (p *Provider) CopyFromDeployment(ctx context.Context, id types.DeploymentID, opts types.CopyOptions) (<-chan types.ExecOutput, error) {
+	return p.Manager.CopyFromDeployment(ctx, id, opts)
+}
+
+func (p *Provider) WatchEvents(ctx context.Context, id types.DeploymentID) (<-chan types.ServiceEvent, error) {
+	return p.Manager.WatchEvents(ctx, id)
+}
+
+func (p *Provider) GetKubeSettings(ctx context.Context) (*types.KubeSettings, error) {
+	return p.Manager.GetKubeSettings(ctx)
+}
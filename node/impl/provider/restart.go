@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/manifest"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RestartTimeout bounds how long a restart waits for the rollout to complete
+var RestartTimeout = 5 * time.Minute
+
+var rolloutPollInterval = 2 * time.Second
+
+// RestartDeployment restarts the pods of a service, or of every service when empty. The services
+// are restarted together, the pods of each one are replaced progressively by its rolling update.
+// The progress is recorded as events of the workloads, and it returns once the rollouts completed.
+func (m *manager) RestartDeployment(ctx context.Context, id types.DeploymentID, service string) error {
+	deploymentID := manifest.DeploymentID{ID: string(id)}
+	ns := builder.DidNS(deploymentID)
+
+	names := []string{service}
+	if service == "" {
+		var err error
+		names, err = m.workloadNames(ctx, ns)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("deployment %s has no service", id)
+	}
+
+	restartedAt := time.Now()
+	refs := make([]*corev1.ObjectReference, 0, len(names))
+	for _, name := range names {
+		ref, err := m.kc.Restart(ctx, ns, name, restartedAt)
+		if err != nil {
+			return fmt.Errorf("restart service %s: %w", name, err)
+		}
+
+		m.recordEvent(ref, corev1.EventTypeNormal, "Restarting", "rolling restart requested")
+		refs = append(refs, ref)
+	}
+
+	return m.waitRollout(ctx, refs)
+}
+
+// workloadNames returns the names of the deployments and the stateful sets of the namespace
+func (m *manager) workloadNames(ctx context.Context, ns string) ([]string, error) {
+	deploymentList, err := m.kc.ListDeployments(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSetList, err := m.kc.ListStatefulSets(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(deploymentList.Items)+len(statefulSetList.Items))
+	for _, deployment := range deploymentList.Items {
+		names = append(names, deployment.Name)
+	}
+	for _, statefulSet := range statefulSetList.Items {
+		names = append(names, statefulSet.Name)
+	}
+
+	return names, nil
+}
+
+func (m *manager) waitRollout(ctx context.Context, refs []*corev1.ObjectReference) error {
+	ctx, cancel := context.WithTimeout(ctx, RestartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+
+	progress := make(map[string]string)
+	pending := refs
+	for {
+		var inProgress []*corev1.ObjectReference
+		for _, ref := range pending {
+			message, done, err := m.kc.RolloutStatus(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("rollout status of service %s: %w", ref.Name, err)
+			}
+
+			if done {
+				m.recordEvent(ref, corev1.EventTypeNormal, "Restarted", message)
+				continue
+			}
+
+			if progress[ref.Name] != message {
+				progress[ref.Name] = message
+				m.recordEvent(ref, corev1.EventTypeNormal, "RolloutProgress", message)
+			}
+			inProgress = append(inProgress, ref)
+		}
+
+		pending = inProgress
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			names := make([]string, 0, len(pending))
+			for _, ref := range pending {
+				names = append(names, ref.Name)
+				m.recordEvent(ref, corev1.EventTypeWarning, "RestartTimeout", fmt.Sprintf("rollout not complete after %s: %s", RestartTimeout, progress[ref.Name]))
+			}
+			return fmt.Errorf("rollout of %s not complete: %w", strings.Join(names, ", "), ctx.Err())
+		}
+	}
+}
+
+// recordEvent records an event of the workload, failures are only logged as the events are informative
+func (m *manager) recordEvent(ref *corev1.ObjectReference, eventType, reason, message string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := m.kc.RecordEvent(ctx, ref, eventType, reason, message); err != nil {
+		log.Errorf("record event %s of %s/%s: %v", reason, ref.Namespace, ref.Name, err)
+	}
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/Filecoin-Titan/titan-container/api/types"
+	"github.com/Filecoin-Titan/titan-container/node/config"
+	"github.com/Filecoin-Titan/titan-container/node/impl/provider/kube/builder"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// kubeSettings maps the [Kube] section of the provider config to the builder settings
+func kubeSettings(cfg config.KubeCfg) builder.Settings {
+	settings := builder.NewDefaultSettings()
+	if cfg.ServiceType != "" {
+		settings.DeploymentServiceType = corev1.ServiceType(cfg.ServiceType)
+	}
+	settings.DeploymentIngressStaticHosts = cfg.IngressStaticHosts
+	settings.DeploymentIngressDomain = cfg.IngressDomain
+	settings.DeploymentIngressExposeLBHosts = cfg.IngressExposeLBHosts
+	settings.DeploymentIngressClusterIssuer = cfg.IngressClusterIssuer
+	settings.ClusterPublicHostname = cfg.ClusterPublicHostname
+	settings.NetworkPoliciesEnabled = cfg.NetworkPoliciesEnabled
+	settings.CPUCommitLevel = cfg.CPUCommitLevel
+	settings.GPUCommitLevel = cfg.GPUCommitLevel
+	settings.MemoryCommitLevel = cfg.MemoryCommitLevel
+	settings.StorageCommitLevel = cfg.StorageCommitLevel
+	settings.DeploymentRuntimeClass = cfg.RuntimeClass
+	settings.DockerImagePullSecretsName = cfg.ImagePullSecretsName
+	return settings
+}
+
+// settings returns the builder settings of the current provider config, the config
+// file is read again so that changes apply without restarting the provider
+func (m *manager) settings() (builder.Settings, error) {
+	cfg := m.providerCfg.Kube
+	if m.getConfig != nil {
+		current, err := m.getConfig()
+		if err != nil {
+			return builder.Settings{}, xerrors.Errorf("load provider config: %w", err)
+		}
+		cfg = current.Kube
+	}
+
+	settings := kubeSettings(cfg)
+	if err := builder.ValidateSettings(settings); err != nil {
+		return builder.Settings{}, err
+	}
+	return settings, nil
+}
+
+// GetKubeSettings returns the settings the next deployments are generated with
+func (m *manager) GetKubeSettings(ctx context.Context) (*types.KubeSettings, error) {
+	settings, err := m.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.KubeSettings{
+		ServiceType:            string(settings.DeploymentServiceType),
+		IngressStaticHosts:     settings.DeploymentIngressStaticHosts,
+		IngressDomain:          settings.DeploymentIngressDomain,
+		IngressExposeLBHosts:   settings.DeploymentIngressExposeLBHosts,
+		IngressClusterIssuer:   settings.DeploymentIngressClusterIssuer,
+		ClusterPublicHostname:  settings.ClusterPublicHostname,
+		NetworkPoliciesEnabled: settings.NetworkPoliciesEnabled,
+		CPUCommitLevel:         settings.CPUCommitLevel,
+		GPUCommitLevel:         settings.GPUCommitLevel,
+		MemoryCommitLevel:      settings.MemoryCommitLevel,
+		StorageCommitLevel:     settings.StorageCommitLevel,
+		RuntimeClass:           settings.DeploymentRuntimeClass,
+		ImagePullSecretsName:   settings.DockerImagePullSecretsName,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 
 	"github.com/Filecoin-Titan/titan-container/api"
+	"github.com/Filecoin-Titan/titan-container/lib/cryptobox"
 	"github.com/Filecoin-Titan/titan-container/node/modules/dtypes"
 	"github.com/Filecoin-Titan/titan-container/node/repo"
 	"github.com/Filecoin-Titan/titan-container/node/types"
@@ -17,6 +18,9 @@ import (
 const (
 	JWTSecretName   = "auth-jwt-private" //nolint:gosec
 	KTJwtHmacSecret = "jwt-hmac-secret"  //nolint:gosec
+
+	CredentialsKeyName = "credentials-key" //nolint:gosec
+	KTAesGcmKey        = "aes-gcm-key"
 )
 
 type JwtPayload struct {
@@ -66,3 +70,30 @@ func APISecret(keystore types.KeyStore, lr repo.LockedRepo) (*dtypes.APIAlg, err
 
 	return (*dtypes.APIAlg)(jwt.NewHS256(key.PrivateKey)), nil
 }
+
+// CredentialsBox returns the box sealing the secrets of the deployments: the registry
+// credentials, the secret env and the TLS keys. Its key is generated on the first start
+func CredentialsBox(keystore types.KeyStore) (*cryptobox.Box, error) {
+	key, err := keystore.Get(CredentialsKeyName)
+	if errors.Is(err, types.ErrKeyInfoNotFound) {
+		log.Warn("Generating new credentials key")
+
+		sk, err := cryptobox.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		key = types.KeyInfo{
+			Type:       KTAesGcmKey,
+			PrivateKey: sk,
+		}
+
+		if err := keystore.Put(CredentialsKeyName, key); err != nil {
+			return nil, xerrors.Errorf("writing credentials key: %w", err)
+		}
+	} else if err != nil {
+		return nil, xerrors.Errorf("could not get credentials key: %w", err)
+	}
+
+	return cryptobox.NewBox(key.PrivateKey)
+}
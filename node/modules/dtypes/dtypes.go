@@ -31,3 +31,7 @@ type SetManagerConfigFunc func(cfg config.ManagerCfg) error
 // GetManagerConfigFunc is a function which is used to
 // get the sealing config.
 type GetManagerConfigFunc func() (config.ManagerCfg, error)
+
+// GetProviderConfigFunc is a function which is used to
+// get the provider config.
+type GetProviderConfigFunc func() (config.ProviderCfg, error)
@@ -0,0 +1,24 @@
+package modules
+
+import (
+	"github.com/Filecoin-Titan/titan-container/node/config"
+	"github.com/Filecoin-Titan/titan-container/node/repo"
+)
+
+// NewGetProviderConfigFunc creates a function to get the provider config
+func NewGetProviderConfigFunc(r repo.LockedRepo) func() (config.ProviderCfg, error) {
+	return func() (out config.ProviderCfg, err error) {
+		raw, err := r.Config()
+		if err != nil {
+			return
+		}
+
+		scfg, ok := raw.(*config.ProviderCfg)
+		if !ok {
+			return
+		}
+
+		out = *scfg
+		return
+	}
+}
@@ -58,14 +58,15 @@ func ServeRPC(h http.Handler, id string, addr string) (StopFunc, error) {
 // ManagerHandler returns a manager handler, to be mounted as-is on the server.
 func ManagerHandler(a api.Manager, permissioned bool, opts ...jsonrpc.ServerOption) (http.Handler, error) {
 	m := mux.NewRouter()
+	readerHandler, readerServerOpt := rpcenc.ReaderParamDecoder()
 
 	serveRpc := func(path string, hnd interface{}) {
-		rpcServer := jsonrpc.NewServer(append(opts, jsonrpc.WithServerErrors(api.RPCErrors))...)
+		rpcServer := jsonrpc.NewServer(append(opts, jsonrpc.WithServerErrors(api.RPCErrors), readerServerOpt)...)
 		rpcServer.Register("titan", hnd)
 
 		var handler http.Handler = rpcServer
 		if permissioned {
-			handler = mhandler.New(&auth.Handler{Verify: a.AuthVerify, Next: rpcServer.ServeHTTP})
+			handler = mhandler.NewWithOwner(a.AuthVerifyPayload, rpcServer.ServeHTTP)
 		}
 
 		m.Handle(path, handler)
@@ -77,6 +78,7 @@ func ManagerHandler(a api.Manager, permissioned bool, opts ...jsonrpc.ServerOpti
 	}
 
 	serveRpc("/rpc/v0", fnapi)
+	m.Handle("/rpc/streams/v0/push/{uuid}", readerHandler)
 	m.PathPrefix("/").Handler(http.DefaultServeMux) // pprof
 
 	return m, nil